@@ -18,7 +18,8 @@ func main() {
 		os.Exit(2)
 	}
 
-	appConf, err := appcfg.Load(filepath.Join(cwd, "configs", "app.yaml"))
+	appPath := filepath.Join(cwd, "configs", "app.yaml")
+	appConf, err := appcfg.Load(appPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "load app config: %v (use defaults: ru/info)\n", err)
 		appConf = &appcfg.Config{Language: "ru", LogLevel: "info"}
@@ -53,5 +54,6 @@ func main() {
 	r := cli.NewRunner()
 	r.HideSecretsInConsole = appConf.HideSecretsInConsole
 	r.Workers = workers
+	r.AppConfigPath = appPath
 	r.Run()
 }
@@ -1,17 +1,25 @@
 package cli
 
 import (
+	"WalletTools/internal/crypto"
 	"WalletTools/internal/generator"
+	"WalletTools/internal/mnemonic"
 	"WalletTools/internal/ops/encdec"
+	"WalletTools/internal/ops/splitkey"
+	"WalletTools/pkg/appcfg"
+	"WalletTools/pkg/config"
 	"WalletTools/pkg/logx"
 	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"golang.org/x/term"
 )
 
@@ -19,6 +27,7 @@ type Runner struct {
 	in                   *bufio.Reader
 	HideSecretsInConsole bool
 	Workers              int
+	AppConfigPath        string // configs/app.yaml, for the benchmark recommendation
 }
 
 func NewRunner() *Runner {
@@ -92,18 +101,39 @@ func (r *Runner) Run() {
 		fmt.Println("2) Generate by Mnemonic")
 		fmt.Println("3) Encrypt raw → keystore")
 		fmt.Println("4) Decrypt keystore → raw")
+		fmt.Println("5) Benchmark & tune workers")
+		fmt.Println("6) Split-key search (partial keys only)")
+		fmt.Println("7) Combine split keys")
+		fmt.Println("8) CREATE2 contract address search")
+		fmt.Println("9) CREATE deployer key search")
+		fmt.Println("10) Scan addresses of an existing mnemonic")
+		fmt.Println("11) Passphrase search for an existing mnemonic")
 		fmt.Println("Press enter to exit")
 		fmt.Print("> ")
 
 		switch strings.ToLower(r.prompt()) {
 		case "1":
-			r.handleGenPriv()
+			r.handleGenPriv(generator.SourcePrivKey)
 		case "2":
 			r.handleGenMnemonic()
 		case "3":
 			r.handleEncrypt()
 		case "4":
 			r.handleDecrypt()
+		case "5":
+			r.handleBenchmark()
+		case "6":
+			r.handleGenSplit()
+		case "7":
+			r.handleCombine()
+		case "8":
+			r.handleGenCreate2()
+		case "9":
+			r.handleGenPriv(generator.SourceCreate)
+		case "10":
+			r.handleGenScan()
+		case "11":
+			r.handleGenPassphrase()
 		case "":
 			return
 		default:
@@ -112,8 +142,24 @@ func (r *Runner) Run() {
 	}
 }
 
-// handleGenPriv — private key generation.
-func (r *Runner) handleGenPriv() {
+// handleGenPriv — private key generation; with SourceCreate the patterns
+// apply to the contract addresses the keys deploy.
+func (r *Runner) handleGenPriv(source generator.Source) {
+	var maxNonce uint64
+	for source == generator.SourceCreate {
+		fmt.Print("Match deployments with nonces 0..N, N (default 0): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err == nil {
+			maxNonce = n
+			break
+		}
+		fmt.Println("Invalid nonce. Try again.")
+	}
+
 	fmt.Print("Encrypt to keystore? (y/n): ")
 	yn := strings.ToLower(r.prompt())
 	encrypt := yn == "y" || yn == "yes"
@@ -138,18 +184,78 @@ func (r *Runner) handleGenPriv() {
 		}
 	}
 
+	fmt.Print("Deterministic seeded search with checkpoint, resumable? (y/N): ")
+	yn = strings.ToLower(r.prompt())
+	seeded := yn == "y" || yn == "yes"
+
+	var seedPwd, resumeDir string
+	var acceptChanged bool
+	incremental := true
+	if seeded {
+		fmt.Print("Resume directory (Enter for a new search): ")
+		resumeDir = r.prompt()
+		var err error
+		if resumeDir != "" {
+			seedPwd, err = readNonEmptyPasswordLoop("Seed password: ")
+		} else {
+			var set bool
+			seedPwd, set, err = readPasswordWithConfirmOrSkip(
+				"Seed password (Enter to cancel): ",
+				"Repeat password: ",
+			)
+			if err == nil && !set {
+				fmt.Println("Password skipped — seeded search canceled.")
+				return
+			}
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if resumeDir != "" {
+			changed, err := generator.ResumePatternsChanged(resumeDir, "configs/patterns.yaml")
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if changed {
+				fmt.Print("Patterns changed since the checkpoint; keys already tested will not be checked against them. Resume anyway? (y/N): ")
+				yn = strings.ToLower(r.prompt())
+				if yn != "y" && yn != "yes" {
+					fmt.Println("Resume canceled.")
+					return
+				}
+				acceptChanged = true
+			}
+		}
+	} else {
+		fmt.Print("Incremental key search, much faster? (Y/n): ")
+		yn = strings.ToLower(r.prompt())
+		incremental = yn == "" || yn == "y" || yn == "yes"
+	}
+
 	opt := generator.Options{
-		Source:           generator.SourcePrivKey,
-		Encrypt:          encrypt,
-		KeystorePassword: pwd,
-		LogsBase:         "logs",
-		PassHint:         hint,
-		PatternsPath:     "configs/patterns.yaml",
-		CaseMaskedOut:    r.HideSecretsInConsole,
-		Workers:          r.Workers,
+		Source:                source,
+		CreateMaxNonce:        maxNonce,
+		Encrypt:               encrypt,
+		KeystorePassword:      pwd,
+		Incremental:           incremental,
+		Seeded:                seeded,
+		SeedPassword:          seedPwd,
+		ResumeDir:             resumeDir,
+		ResumeChangedPatterns: acceptChanged,
+		LogsBase:              "logs",
+		PassHint:              hint,
+		PatternsPath:          "configs/patterns.yaml",
+		CaseMaskedOut:         r.HideSecretsInConsole,
+		Workers:               r.Workers,
+	}
+	if source != generator.SourceCreate {
+		r.promptFormat(&opt)
 	}
+	r.promptLimits(&opt)
 	ctx := withInterrupt(context.Background())
-	logx.S().Infow("start generation", "mode", "private", "encrypt", encrypt)
+	logx.S().Infow("start generation", "mode", source, "encrypt", encrypt, "incremental", incremental, "seeded", seeded, "resume", resumeDir)
 	if err := generator.Run(ctx, opt); err != nil {
 		logx.S().Errorw("generation error", "err", err)
 	} else {
@@ -196,10 +302,54 @@ func (r *Runner) handleGenMnemonic() {
 		}
 	}
 
+	strength := 128
+	for {
+		fmt.Print("Words: 12, 15, 18, 21 or 24 (default 12): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		if n := atoiSafe(s); n > 0 && mnemonic.ValidStrength(n/3*32) && n%3 == 0 {
+			strength = n / 3 * 32
+			break
+		}
+		fmt.Println("Invalid word count. Try again.")
+	}
+
+	lang := mnemonic.DefaultLang
+	for {
+		fmt.Printf("Wordlist: %s (default %s): ", strings.Join(mnemonic.Languages(), ", "), mnemonic.DefaultLang)
+		s := strings.ToLower(r.prompt())
+		if s == "" {
+			break
+		}
+		if _, err := mnemonic.NewWordlist(s); err == nil {
+			lang = s
+			break
+		}
+		fmt.Println("Unknown wordlist. Try again.")
+	}
+
+	var paths []string
+	for {
+		fmt.Print("Derivation paths, comma-separated templates with {i} or bip44, ledger, mew, tron, cosmos (default bip44, cosmos for Cosmos formats): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		var err error
+		if paths, err = mnemonic.ParsePathTemplates(strings.Split(s, ",")); err == nil {
+			break
+		}
+		fmt.Println("Error:", err)
+	}
+
 	opt := generator.Options{
 		Source:        generator.SourceMnemonic,
-		WordsStrength: 128,
+		WordsStrength: strength,
+		WordsLang:     lang,
 		DeriveN:       deriveN,
+		DerivePaths:   paths,
 		Passphrase:    passStr,
 		LogsBase:      "logs",
 		PassHint:      hint,
@@ -207,10 +357,12 @@ func (r *Runner) handleGenMnemonic() {
 		CaseMaskedOut: r.HideSecretsInConsole,
 		Workers:       r.Workers,
 	}
+	r.promptFormat(&opt)
+	r.promptLimits(&opt)
 
 	ctx := withInterrupt(context.Background())
 
-	logx.S().Infow("start generation", "mode", "mnemonic", "derive_n", deriveN, "use_passphrase", usePP)
+	logx.S().Infow("start generation", "mode", "mnemonic", "words", strength/32*3, "wordlist", lang, "derive_n", deriveN, "derive_paths", paths, "use_passphrase", usePP)
 	if err := generator.Run(ctx, opt); err != nil {
 		logx.S().Errorw("generation error", "err", err)
 	} else {
@@ -220,6 +372,245 @@ func (r *Runner) handleGenMnemonic() {
 	passStr = ""
 }
 
+// handleGenSplit — split-key search against the owner's public key. This
+// machine only ever sees partial keys.
+func (r *Runner) handleGenSplit() {
+	var pub string
+	for {
+		fmt.Print("Owner public key, hex (Enter to cancel): ")
+		pub = r.prompt()
+		if pub == "" {
+			return
+		}
+		if _, err := crypto.ParsePubKey(pub); err == nil {
+			break
+		}
+		fmt.Println("Invalid public key. Try again.")
+	}
+
+	opt := generator.Options{
+		Source:        generator.SourceSplitKey,
+		SplitPubKey:   pub,
+		LogsBase:      "logs",
+		PatternsPath:  "configs/patterns.yaml",
+		CaseMaskedOut: r.HideSecretsInConsole,
+		Workers:       r.Workers,
+	}
+	r.promptFormat(&opt)
+	r.promptLimits(&opt)
+	ctx := withInterrupt(context.Background())
+	logx.S().Infow("start generation", "mode", "split", "public_key", pub)
+	if err := generator.Run(ctx, opt); err != nil {
+		logx.S().Errorw("generation error", "err", err)
+	} else {
+		logx.S().Infow("generation done")
+	}
+}
+
+// handleCombine — owner side of the split-key search: secret + partial keys
+// from inputs/combine/*.jsonl → final keys.
+func (r *Runner) handleCombine() {
+	secretHex, err := readNonEmptyPasswordLoop("Owner secret key, hex: ")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	secret, err := gethcrypto.HexToECDSA(strings.TrimPrefix(secretHex, "0x"))
+	secretHex = ""
+	if err != nil {
+		fmt.Println("Error: invalid secret key:", err)
+		return
+	}
+	fmt.Println("Owner public key:", crypto.PubKeyHex(secret))
+
+	pwd, _, err := readPasswordWithConfirmOrSkip(
+		"Keystore password for the final keys (Enter to skip): ",
+		"Repeat password: ",
+	)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	_ = splitkey.CombinePartials(
+		withInterrupt(context.Background()),
+		splitkey.CombineOptions{
+			InputsBaseDir:        "inputs",
+			LogsBase:             "logs",
+			Secret:               secret,
+			Password:             pwd,
+			HideSecretsInConsole: r.HideSecretsInConsole,
+		},
+	)
+}
+
+// handleGenCreate2 — salt search for a CREATE2 factory. No keys involved.
+func (r *Runner) handleGenCreate2() {
+	fmt.Print("Deployer (factory) address: ")
+	deployer := r.prompt()
+	fmt.Print("Init code hash, keccak256 of the init code: ")
+	initCodeHash := r.prompt()
+
+	opt := generator.Options{
+		Source:              generator.SourceCreate2,
+		Create2Deployer:     deployer,
+		Create2InitCodeHash: initCodeHash,
+		LogsBase:            "logs",
+		PatternsPath:        "configs/patterns.yaml",
+		CaseMaskedOut:       r.HideSecretsInConsole,
+		Workers:             r.Workers,
+	}
+	r.promptLimits(&opt)
+	ctx := withInterrupt(context.Background())
+	logx.S().Infow("start generation", "mode", "create2", "deployer", deployer, "init_code_hash", initCodeHash)
+	if err := generator.Run(ctx, opt); err != nil {
+		logx.S().Errorw("generation error", "err", err)
+	} else {
+		logx.S().Infow("generation done")
+	}
+}
+
+// handleGenScan — vanity address inside an existing wallet: the mnemonic is
+// scanned over accounts and indexes, and the first matching path is reported.
+func (r *Runner) handleGenScan() {
+	mn := readMnemonic()
+	if mn == "" {
+		return
+	}
+
+	pass, _, err := readPasswordWithConfirmOrSkip(
+		"BIP-39 passphrase (Enter to skip): ",
+		"Repeat passphrase: ",
+	)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Printf("Path template with {i} and optional {a} (default %s): ", mnemonic.DefaultScanPath)
+	path := r.prompt()
+
+	fmt.Print("Accounts to scan (default 1): ")
+	accounts := max(atoiSafe(r.prompt()), 1)
+
+	indexes := 0
+	if accounts == 1 {
+		fmt.Print("Indexes per account (default all 2^31): ")
+		indexes = atoiSafe(r.prompt())
+	}
+	for indexes <= 0 && accounts > 1 {
+		fmt.Print("Indexes per account (required with several accounts): ")
+		indexes = atoiSafe(r.prompt())
+	}
+
+	opt := generator.Options{
+		Source:        generator.SourceScan,
+		FixedMnemonic: mn,
+		ScanPath:      path,
+		ScanAccounts:  accounts,
+		ScanIndexes:   indexes,
+		Passphrase:    pass,
+		LogsBase:      "logs",
+		PatternsPath:  "configs/patterns.yaml",
+		CaseMaskedOut: r.HideSecretsInConsole,
+		Workers:       r.Workers,
+	}
+	r.promptFormat(&opt)
+	r.promptLimits(&opt)
+	ctx := withInterrupt(context.Background())
+	logx.S().Infow("start generation", "mode", "scan", "path", path, "accounts", accounts, "indexes", indexes, "use_passphrase", pass != "")
+	if err := generator.Run(ctx, opt); err != nil {
+		logx.S().Errorw("generation error", "err", err)
+	} else {
+		logx.S().Infow("generation done")
+	}
+
+	mn, pass = "", ""
+}
+
+// handleGenPassphrase — new wallets from one backed-up mnemonic: random
+// passphrases are tried until one yields a matching address.
+func (r *Runner) handleGenPassphrase() {
+	mn := readMnemonic()
+	if mn == "" {
+		return
+	}
+
+	var words, length int
+	var charset string
+	fmt.Print("Diceware words from the mnemonic's wordlist (Enter for random characters): ")
+	if words = atoiSafe(r.prompt()); words <= 0 {
+		fmt.Printf("Charset (default %s): ", generator.DefaultPassCharset)
+		charset = r.prompt()
+		fmt.Print("Length (default 12): ")
+		length = atoiSafe(r.prompt())
+	}
+
+	fmt.Print("Derive N addresses per passphrase (default 5): ")
+	deriveN := atoiSafe(r.prompt())
+	if deriveN <= 0 {
+		deriveN = 5
+	}
+
+	var paths []string
+	for {
+		fmt.Print("Derivation paths, comma-separated templates with {i} or bip44, ledger, mew, tron (default bip44): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		var err error
+		if paths, err = mnemonic.ParsePathTemplates(strings.Split(s, ",")); err == nil {
+			break
+		}
+		fmt.Println("Error:", err)
+	}
+
+	opt := generator.Options{
+		Source:        generator.SourcePassphrase,
+		FixedMnemonic: mn,
+		PassWords:     words,
+		PassCharset:   charset,
+		PassLength:    length,
+		DeriveN:       deriveN,
+		DerivePaths:   paths,
+		LogsBase:      "logs",
+		PatternsPath:  "configs/patterns.yaml",
+		CaseMaskedOut: r.HideSecretsInConsole,
+		Workers:       r.Workers,
+	}
+	r.promptFormat(&opt)
+	r.promptLimits(&opt)
+	ctx := withInterrupt(context.Background())
+	logx.S().Infow("start generation", "mode", "passphrase", "diceware_words", words, "length", length, "derive_n", deriveN, "derive_paths", paths)
+	if err := generator.Run(ctx, opt); err != nil {
+		logx.S().Errorw("generation error", "err", err)
+	} else {
+		logx.S().Infow("generation done")
+	}
+
+	mn = ""
+}
+
+// readMnemonic — hidden input of an existing mnemonic, checksum included;
+// empty when the user cancels.
+func readMnemonic() string {
+	for {
+		s, err := readPassword("Mnemonic, hidden (Enter to cancel): ")
+		if err != nil {
+			fmt.Println("Error:", err)
+			return ""
+		}
+		if s == "" {
+			return ""
+		}
+		if _, err := mnemonic.DetectWordlist(s, ""); err == nil {
+			return s
+		}
+		fmt.Println("Invalid mnemonic. Try again.")
+	}
+}
+
 // handleEncrypt — manual encryption of private keys in the keystore.
 func (r *Runner) handleEncrypt() {
 	p, set, err := readPasswordWithConfirmOrSkip(
@@ -268,6 +659,135 @@ func (r *Runner) handleDecrypt() {
 	)
 }
 
+// promptFormat asks for the address form the patterns are matched against.
+// Bitcoin and Solana formats are offered to the plain private key search
+// only, Cosmos formats to it and the mnemonic search.
+func (r *Runner) promptFormat(opt *generator.Options) {
+	plain := opt.Source == generator.SourcePrivKey && !opt.Encrypt
+	offered := func(f config.AddressFormat) bool {
+		switch {
+		case f.Bitcoin() || f == config.FormatSolana:
+			return plain
+		case f.Script() == "cosmos":
+			return plain || opt.Source == generator.SourceMnemonic
+		}
+		return true
+	}
+	var names []string
+	for _, f := range config.Formats() {
+		if offered(f) {
+			names = append(names, string(f))
+		}
+	}
+	list := strings.Join(names, ", ")
+	if offered(config.FormatCosmos) {
+		list += " or cosmos-<hrp> for other Cosmos chains"
+	}
+	for {
+		fmt.Printf("Address format: %s (default %s): ", list, config.FormatEVM)
+		f, err := config.ParseFormat(r.prompt())
+		if err == nil && !offered(f) {
+			err = fmt.Errorf("%s addresses are not searched in this mode", f)
+		}
+		if err == nil {
+			opt.Format = f
+			return
+		}
+		fmt.Println("Error:", err)
+	}
+}
+
+// promptLimits asks for the optional run limits; Enter keeps each unlimited.
+func (r *Runner) promptLimits(opt *generator.Options) {
+	for {
+		fmt.Print("Stop after N attempts (Enter = unlimited): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err == nil {
+			opt.MaxAttempts = n
+			break
+		}
+		fmt.Println("Invalid number. Try again.")
+	}
+	for {
+		fmt.Print("Stop after duration, e.g. 30m or 12h (Enter = unlimited): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		d, err := time.ParseDuration(s)
+		if err == nil && d > 0 {
+			opt.MaxDuration = d
+			break
+		}
+		fmt.Println("Invalid duration. Try again.")
+	}
+	for {
+		fmt.Print("Stop after N results in total (Enter = unlimited): ")
+		s := r.prompt()
+		if s == "" {
+			break
+		}
+		n, err := strconv.Atoi(s)
+		if err == nil && n > 0 {
+			opt.MaxHits = n
+			break
+		}
+		fmt.Println("Invalid number. Try again.")
+	}
+}
+
+// handleBenchmark — measure throughput per configuration and worker count,
+// optionally saving the recommended worker count as cores in app.yaml.
+func (r *Runner) handleBenchmark() {
+	fmt.Print("Duration per configuration (default 3s): ")
+	d := 3 * time.Second
+	if s := r.prompt(); s != "" {
+		if v, err := time.ParseDuration(s); err == nil && v > 0 {
+			d = v
+		}
+	}
+	fmt.Print("Write CPU profile? (y/n): ")
+	yn := strings.ToLower(r.prompt())
+
+	res, err := generator.Benchmark(withInterrupt(context.Background()), generator.BenchOptions{
+		LogsBase:     "logs",
+		PatternsPath: "configs/patterns.yaml",
+		Duration:     d,
+		CPUProfile:   yn == "y" || yn == "yes",
+	})
+	if err != nil {
+		logx.S().Errorw("benchmark error", "err", err)
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%-42s %8s %16s\n", "config", "workers", "addr/sec")
+	for _, br := range res {
+		fmt.Printf("%-42s %8d %16.2f\n", br.Config, br.Workers, br.Rate)
+	}
+	best := generator.BestWorkers(res)
+	fmt.Printf("Recommended workers: %d (current: %d)\n", best, r.Workers)
+	if best <= 0 || r.AppConfigPath == "" {
+		return
+	}
+
+	fmt.Printf("Save cores: %d to %s? (y/n): ", best, r.AppConfigPath)
+	yn = strings.ToLower(r.prompt())
+	if yn != "y" && yn != "yes" {
+		return
+	}
+	if err := appcfg.SaveCores(r.AppConfigPath, best); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	r.Workers = best
+	fmt.Println("Saved.")
+}
+
 func atoiSafe(s string) int {
 	var n int
 	_, _ = fmt.Sscan(s, &n)
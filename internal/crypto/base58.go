@@ -0,0 +1,66 @@
+package crypto
+
+import "crypto/sha256"
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Pow5 is 58^5, the largest power of 58 below 2^32: one division of
+// the 32-bit limbs by it yields five digits.
+const base58Pow5 = 58 * 58 * 58 * 58 * 58
+
+// appendBase58 appends the base58 form of b, a big-endian number of at most
+// 64 bytes, with one '1' per leading zero byte as Bitcoin encodes it.
+func appendBase58(dst, b []byte) []byte {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	var limbs [16]uint32
+	n := (len(b) + 3) / 4
+	for i, c := range b {
+		j := len(b) - 1 - i // byte position from the least significant end
+		limbs[n-1-j/4] |= uint32(c) << (8 * (j % 4))
+	}
+
+	var digits [90]byte // least significant first, five at a time
+	nd := 0
+	for start := 0; start < n; {
+		var rem uint64
+		for i := start; i < n; i++ {
+			cur := rem<<32 | uint64(limbs[i])
+			limbs[i] = uint32(cur / base58Pow5)
+			rem = cur % base58Pow5
+		}
+		for k := 0; k < 5; k++ {
+			digits[nd] = base58Alphabet[rem%58]
+			rem /= 58
+			nd++
+		}
+		for start < n && limbs[start] == 0 {
+			start++
+		}
+	}
+	for nd > 0 && digits[nd-1] == '1' {
+		nd--
+	}
+
+	for i := 0; i < zeros; i++ {
+		dst = append(dst, '1')
+	}
+	for i := nd - 1; i >= 0; i-- {
+		dst = append(dst, digits[i])
+	}
+	return dst
+}
+
+// appendBase58Check appends the base58 form of payload followed by the first
+// four bytes of its double sha256.
+func appendBase58Check(dst, payload []byte) []byte {
+	var buf [68]byte
+	n := copy(buf[:], payload)
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+	copy(buf[n:], h[:4])
+	return appendBase58(dst, buf[:n+4])
+}
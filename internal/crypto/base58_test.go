@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestAppendBase58(t *testing.T) {
+	tests := []struct {
+		in, want string // in is hex
+	}{
+		{"", ""},
+		{"00", "1"},
+		{"0000", "11"},
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"636363", "aPEr"},
+		{"48656c6c6f20576f726c6421", "2NEpo7TZRRrLZSi2U"},
+		{"00000000000000000000", "1111111111"},
+		{"000111d38e5fc9071ffcd20b4a763cc9ae4f252bb4e48fd66a835e252ada93ff480d6dd43dc62a641155a5", "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", "11111111111111111111111111111111"},
+	}
+	for _, tt := range tests {
+		b, _ := hex.DecodeString(tt.in)
+		if got := string(appendBase58(nil, b)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAppendBase58Check(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"00751e76e8199196d454941c45d1b3a323f1433bd6", "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{"6f751e76e8199196d454941c45d1b3a323f1433bd6", "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{"410000000000000000000000000000000000000000", "T9yD14Nj9j7xAB4dbGeiX9h8unkKHxuWwb"},
+	}
+	for _, tt := range tests {
+		b, _ := hex.DecodeString(tt.in)
+		if got := string(appendBase58Check(nil, b)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
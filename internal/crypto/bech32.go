@@ -0,0 +1,81 @@
+package crypto
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of BIP-173 (witness version 0) and BIP-350 (bech32m,
+// versions 1 and up).
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(chk uint32, v byte) uint32 {
+	top := chk >> 25
+	chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+	for i, g := range [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3} {
+		if top>>i&1 == 1 {
+			chk ^= g
+		}
+	}
+	return chk
+}
+
+// appendSegwit appends the segwit address hrp + "1" + version + program +
+// checksum. The program is at most 40 bytes.
+func appendSegwit(dst []byte, hrp string, version byte, program []byte) []byte {
+	var buf [65]byte // version and 5-bit groups of the program
+	data := appendBase32(append(buf[:0], version), program)
+	c := uint32(bech32Const)
+	if version > 0 {
+		c = bech32mConst
+	}
+	return appendBech32(dst, hrp, data, c)
+}
+
+// appendBase32 appends the 5-bit groups of b, the last one zero-padded.
+func appendBase32(dst, b []byte) []byte {
+	var acc uint32
+	bits := 0
+	for _, c := range b {
+		acc = acc<<8 | uint32(c)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			dst = append(dst, byte(acc>>bits&31))
+		}
+	}
+	if bits > 0 {
+		dst = append(dst, byte(acc<<(5-bits)&31))
+	}
+	return dst
+}
+
+// appendBech32 appends hrp + "1" + data + checksum, where data holds 5-bit
+// values and c is bech32Const or bech32mConst.
+func appendBech32(dst []byte, hrp string, data []byte, c uint32) []byte {
+	chk := uint32(1)
+	for i := 0; i < len(hrp); i++ {
+		chk = bech32Polymod(chk, hrp[i]>>5)
+	}
+	chk = bech32Polymod(chk, 0)
+	for i := 0; i < len(hrp); i++ {
+		chk = bech32Polymod(chk, hrp[i]&31)
+	}
+	for _, v := range data {
+		chk = bech32Polymod(chk, v)
+	}
+	for i := 0; i < 6; i++ {
+		chk = bech32Polymod(chk, 0)
+	}
+	chk ^= c
+
+	dst = append(dst, hrp...)
+	dst = append(dst, '1')
+	for _, v := range data {
+		dst = append(dst, bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		dst = append(dst, bech32Charset[chk>>(5*(5-i))&31])
+	}
+	return dst
+}
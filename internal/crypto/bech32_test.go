@@ -0,0 +1,55 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Valid addresses of BIP-173 (version 0) and BIP-350 (versions 1 and up).
+func TestAppendSegwit(t *testing.T) {
+	tests := []struct {
+		hrp     string
+		version byte
+		program string
+		want    string
+	}{
+		{"bc", 0, "751e76e8199196d454941c45d1b3a323f1433bd6", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{"tb", 0, "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7"},
+		{"tb", 0, "000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433", "tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy"},
+		{"bc", 1, "751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6", "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y"},
+		{"bc", 16, "751e", "bc1sw50qgdz25j"},
+		{"bc", 2, "751e76e8199196d454941c45d1b3a323", "bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs"},
+		{"tb", 1, "000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433", "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c"},
+		{"bc", 1, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+	}
+	for _, tt := range tests {
+		p, _ := hex.DecodeString(tt.program)
+		if got := string(appendSegwit(nil, tt.hrp, tt.version, p)); got != tt.want {
+			t.Errorf("%s v%d %s: got %s, want %s", tt.hrp, tt.version, tt.program, got, tt.want)
+		}
+	}
+}
+
+// Valid bech32 and bech32m strings of BIP-173 and BIP-350, as raw 5-bit data.
+func TestAppendBech32(t *testing.T) {
+	all, rev := make([]byte, 32), make([]byte, 32)
+	for i := range all {
+		all[i], rev[i] = byte(i), byte(31-i)
+	}
+	tests := []struct {
+		hrp  string
+		data []byte
+		c    uint32
+		want string
+	}{
+		{"a", nil, bech32Const, "a12uel5l"},
+		{"abcdef", all, bech32Const, "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw"},
+		{"a", nil, bech32mConst, "a1lqfn3a"},
+		{"abcdef", rev, bech32mConst, "abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx"},
+	}
+	for _, tt := range tests {
+		if got := string(appendBech32(nil, tt.hrp, tt.data, tt.c)); got != tt.want {
+			t.Errorf("%s %v: got %s, want %s", tt.hrp, tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"hash"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// BTCNet holds the encoding parameters of a Bitcoin network.
+type BTCNet struct {
+	Name       string
+	PKHVersion byte   // version byte of P2PKH addresses
+	WIFVersion byte   // version byte of WIF private keys
+	HRP        string // human-readable part of segwit addresses
+}
+
+var (
+	BTCMainnet = &BTCNet{Name: "mainnet", PKHVersion: 0x00, WIFVersion: 0x80, HRP: "bc"}
+	BTCTestnet = &BTCNet{Name: "testnet", PKHVersion: 0x6f, WIFVersion: 0xef, HRP: "tb"}
+	BTCRegtest = &BTCNet{Name: "regtest", PKHVersion: 0x6f, WIFVersion: 0xef, HRP: "bcrt"}
+)
+
+// BTCNetwork returns the network called name, or nil.
+func BTCNetwork(name string) *BTCNet {
+	for _, n := range []*BTCNet{BTCMainnet, BTCTestnet, BTCRegtest} {
+		if n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// Hash160 is ripemd160(sha256(b)), the key hash of P2PKH and P2WPKH
+// addresses when b is a compressed public key.
+func Hash160(b []byte) [20]byte {
+	s := sha256.Sum256(b)
+	h := ripemd160.New()
+	h.Write(s[:])
+	var out [20]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// hasher160 computes Hash160 with reused state, for hot loops.
+type hasher160 struct {
+	rmd    hash.Hash
+	sum256 [32]byte
+}
+
+func newHasher160() *hasher160 { return &hasher160{rmd: ripemd160.New()} }
+
+func (h *hasher160) sum(b []byte, out *[20]byte) {
+	h.sum256 = sha256.Sum256(b)
+	h.rmd.Reset()
+	h.rmd.Write(h.sum256[:])
+	h.rmd.Sum(out[:0])
+}
+
+// KeyHash is the Hash160 of the compressed public key of priv.
+func KeyHash(priv *ecdsa.PrivateKey) [20]byte {
+	return Hash160(gethcrypto.CompressPubkey(&priv.PublicKey))
+}
+
+// AppendP2PKH appends the legacy base58check address of a key hash.
+func AppendP2PKH(dst []byte, net *BTCNet, h *[20]byte) []byte {
+	var payload [21]byte
+	payload[0] = net.PKHVersion
+	copy(payload[1:], h[:])
+	return appendBase58Check(dst, payload[:])
+}
+
+// AppendP2WPKH appends the native segwit (bech32, version 0) address of a
+// key hash.
+func AppendP2WPKH(dst []byte, net *BTCNet, h *[20]byte) []byte {
+	return appendSegwit(dst, net.HRP, 0, h[:])
+}
+
+// AppendP2TR appends the taproot (bech32m, version 1) address of an x-only
+// output key.
+func AppendP2TR(dst []byte, net *BTCNet, q *[32]byte) []byte {
+	return appendSegwit(dst, net.HRP, 1, q[:])
+}
+
+// WIF is the wallet import format of priv, flagged for the compressed public
+// key that every address of this package uses.
+func WIF(priv *ecdsa.PrivateKey, net *BTCNet) string {
+	var payload [34]byte
+	payload[0] = net.WIFVersion
+	priv.D.FillBytes(payload[1:33])
+	payload[33] = 0x01
+	defer clear(payload[:])
+	return string(appendBase58Check(nil, payload[:]))
+}
+
+// tapTweakTag is sha256("TapTweak"), the BIP-340 tag of taproot tweaks.
+var tapTweakTag = sha256.Sum256([]byte("TapTweak"))
+
+var errTweak = errors.New("taproot tweak out of range")
+
+// TaprootOutputKey is the BIP-86 output key of the internal key pub, a
+// taproot output without script path: Q = P + t·G, where P is pub with an
+// even Y and t = hash_TapTweak(x(P)). It returns x(Q).
+func TaprootOutputKey(pub *secp256k1.PublicKey) ([32]byte, error) {
+	var p, tG, q secp256k1.JacobianPoint
+	pub.AsJacobian(&p)
+	var x [32]byte
+	p.X.PutBytesUnchecked(x[:])
+	if p.Y.IsOdd() {
+		p.Y.Negate(1).Normalize()
+	}
+
+	h := sha256.New()
+	h.Write(tapTweakTag[:])
+	h.Write(tapTweakTag[:])
+	h.Write(x[:])
+	var t [32]byte
+	h.Sum(t[:0])
+	var tk secp256k1.ModNScalar
+	if tk.SetBytes(&t) != 0 {
+		return x, errTweak
+	}
+	secp256k1.ScalarBaseMultNonConst(&tk, &tG)
+	secp256k1.AddNonConst(&p, &tG, &q)
+	q.ToAffine()
+	q.X.PutBytesUnchecked(x[:])
+	return x, nil
+}
@@ -0,0 +1,90 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestKeyOneAddresses(t *testing.T) {
+	k := keyOne(t)
+	h := KeyHash(k)
+	if got, want := hex.EncodeToString(h[:]), "751e76e8199196d454941c45d1b3a323f1433bd6"; got != want {
+		t.Fatalf("key hash: got %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"WIF mainnet", WIF(k, BTCMainnet), "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"},
+		{"WIF testnet", WIF(k, BTCTestnet), "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA"},
+		{"P2PKH mainnet", string(AppendP2PKH(nil, BTCMainnet, &h)), "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{"P2PKH testnet", string(AppendP2PKH(nil, BTCTestnet, &h)), "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{"P2WPKH mainnet", string(AppendP2WPKH(nil, BTCMainnet, &h)), "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{"P2WPKH regtest", string(AppendP2WPKH(nil, BTCRegtest, &h)), "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// The BIP-86 vectors of "abandon … about" at m/86'/0'/0'/0/0, 0/1 and 1/0,
+// given by their internal keys, and keys 1 and 6, whose public keys have
+// an even and an odd Y.
+func TestTaprootOutputKey(t *testing.T) {
+	tests := []struct {
+		pub, output, addr string
+	}{
+		{"02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			"a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+		{"0283dfe85a3151d2517290da461fe2815591ef69f2b18a2ce63f01697a8b313145",
+			"a82f29944d65b86ae6b5e5cc75e294ead6c59391a1edc5e016e3498c67fc7bbb",
+			"bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh"},
+		{"02399f1b2f4393f29a18c937859c5dd8a77350103157eb880f02e8c08214277cef",
+			"882d74e5d0572d5a816cef0041a96b6c1de832f6f9676d9605c44d5e9a97d3dc",
+			"bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7"},
+		{"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "",
+			"bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9"},
+		{"03fff97bd5755eeea420453a14355235d382f6472f8568a18b2f057a1460297556", "",
+			"bc1p4rsld9ryjhte00drc0r23r8ngd63xrzh5s4fvmy6q5yt70xzlsdqcuvtzv"},
+	}
+	for _, tt := range tests {
+		b, _ := hex.DecodeString(tt.pub)
+		pub, err := secp256k1.ParsePubKey(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := TaprootOutputKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(q[:]); tt.output != "" && got != tt.output {
+			t.Errorf("%s: output key %s, want %s", tt.pub, got, tt.output)
+		}
+		if got := string(AppendP2TR(nil, BTCMainnet, &q)); got != tt.addr {
+			t.Errorf("%s: got %s, want %s", tt.pub, got, tt.addr)
+		}
+	}
+
+	// The private key 6 leads to the same output as its public key.
+	k, err := gethcrypto.HexToECDSA("0000000000000000000000000000000000000000000000000000000000000006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := secp256k1.ParsePubKey(gethcrypto.CompressPubkey(&k.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := TaprootOutputKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(AppendP2TR(nil, BTCMainnet, &q)), tests[4].addr; got != want {
+		t.Errorf("key 6: got %s, want %s", got, want)
+	}
+}
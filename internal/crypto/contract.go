@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Create2 computes the CREATE2 addresses
+// keccak256(0xff ++ deployer ++ salt ++ initCodeHash)[12:] of one deployer
+// and init code, reusing its input buffer and hash state. It is not safe for
+// concurrent use.
+type Create2 struct {
+	buf  [1 + 20 + 32 + 32]byte
+	hash gethcrypto.KeccakState
+}
+
+func NewCreate2(deployer common.Address, initCodeHash common.Hash) *Create2 {
+	c := &Create2{hash: gethcrypto.NewKeccakState()}
+	c.buf[0] = 0xff
+	copy(c.buf[1:21], deployer[:])
+	copy(c.buf[53:], initCodeHash[:])
+	return c
+}
+
+// Address returns the contract address for salt.
+func (c *Create2) Address(salt *[32]byte) [20]byte {
+	copy(c.buf[21:53], salt[:])
+	var h [32]byte
+	c.hash.Reset()
+	c.hash.Write(c.buf[:])
+	c.hash.Read(h[:])
+	var a [20]byte
+	copy(a[:], h[12:])
+	return a
+}
+
+// Create computes the CREATE addresses keccak256(rlp([sender, nonce]))[12:],
+// reusing its hash state. It is not safe for concurrent use.
+type Create struct {
+	buf  [1 + 21 + 9]byte
+	hash gethcrypto.KeccakState
+}
+
+func NewCreate() *Create {
+	return &Create{hash: gethcrypto.NewKeccakState()}
+}
+
+// Address returns the address of the contract deployed by sender with nonce.
+func (c *Create) Address(sender *[20]byte, nonce uint64) [20]byte {
+	// rlp: list header, 0x94 ++ sender, then the nonce as an rlp integer.
+	b := c.buf[:1]
+	b = append(b, 0x80+20)
+	b = append(b, sender[:]...)
+	switch {
+	case nonce == 0:
+		b = append(b, 0x80)
+	case nonce < 0x80:
+		b = append(b, byte(nonce))
+	default:
+		n := 0
+		for v := nonce; v > 0; v >>= 8 {
+			n++
+		}
+		b = append(b, 0x80+byte(n))
+		for i := n - 1; i >= 0; i-- {
+			b = append(b, byte(nonce>>(8*i)))
+		}
+	}
+	b[0] = 0xc0 + byte(len(b)-1)
+
+	var h [32]byte
+	c.hash.Reset()
+	c.hash.Write(b)
+	c.hash.Read(h[:])
+	var a [20]byte
+	copy(a[:], h[12:])
+	return a
+}
@@ -0,0 +1,100 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// The examples of EIP-1014.
+func TestCreate2(t *testing.T) {
+	tests := []struct {
+		deployer, salt, initCode, want string
+	}{
+		{"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000",
+			"0x000000000000000000000000feed000000000000000000000000000000000000",
+			"0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0xdeadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+		{"0x00000000000000000000000000000000deadbeef",
+			"0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			"0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x00000000000000000000000000000000deadbeef",
+			"0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			"0x" + strings.Repeat("deadbeef", 11), "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C"},
+		{"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+	for _, tt := range tests {
+		deployer := common.HexToAddress(tt.deployer)
+		salt := common.HexToHash(tt.salt)
+		codeHash := common.BytesToHash(gethcrypto.Keccak256(common.FromHex(tt.initCode)))
+
+		c := NewCreate2(deployer, codeHash)
+		got := common.Address(c.Address((*[32]byte)(&salt)))
+		if got.Hex() != tt.want {
+			t.Errorf("deployer %s salt %s code %.10s…: got %s, want %s", tt.deployer, tt.salt, tt.initCode, got.Hex(), tt.want)
+		}
+		if ref := gethcrypto.CreateAddress2(deployer, salt, codeHash[:]); got != ref {
+			t.Errorf("deployer %s salt %s: got %s, go-ethereum gives %s", tt.deployer, tt.salt, got.Hex(), ref.Hex())
+		}
+	}
+}
+
+// The buffer is reused: a salt does not leak into the next address.
+func TestCreate2Reuse(t *testing.T) {
+	deployer := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	codeHash := common.BytesToHash(gethcrypto.Keccak256([]byte{0xde, 0xad, 0xbe, 0xef}))
+	c := NewCreate2(deployer, codeHash)
+	for i := range 4 {
+		var salt common.Hash
+		salt[31-i] = byte(i + 1)
+		got := common.Address(c.Address((*[32]byte)(&salt)))
+		if ref := gethcrypto.CreateAddress2(deployer, salt, codeHash[:]); got != ref {
+			t.Errorf("salt %s: got %s, go-ethereum gives %s", salt.Hex(), got.Hex(), ref.Hex())
+		}
+	}
+}
+
+// CREATE addresses across the boundaries of the RLP nonce encoding: the
+// empty string for 0, a single byte below 0x80, then length-prefixed bytes.
+func TestCreate(t *testing.T) {
+	sender := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	tests := []struct {
+		nonce uint64
+		want  string
+	}{
+		{0, "0xcd234A471b72ba2F1Ccf0A70FCABA648a5eeCD8d"},
+		{1, "0x343c43A37D37dfF08AE8C4A11544c718AbB4fCF8"},
+		{2, "0xf778B86FA74E846c4f0a1fBd1335FE81c00a0C91"},
+		{3, "0xffFd933A0bC612844eaF0C6Fe3E5b8E9B6C1d19c"},
+		{0x7f, "0x06d9a77f5E4b311Bae8D559DB9CDB4dF94104aA0"},
+		{0x80, "0x08e190dcB7b73F5fcDAbb43e102215c83659A76D"},
+		{0xff, "0x3eF7c1a519E4b4431E317d7839340E3139B03c65"},
+		{0x100, "0x3837C1Ae70354f670550C746580199Ac6a73Cb0a"},
+		{0xffff, "0x65260EECFf4eDeBaBE134f76F1F39a91Defde56C"},
+		{0x10000, "0xf666A819b370D38F44F2573464Da3FbA8479b917"},
+		{1 << 40, "0x4A16ca4eA66191fac142F1a057a251b3B417EfF2"},
+		{1<<64 - 1, "0x9bc924993b60399DF164c3763a964301D3dB95Ca"},
+	}
+	c := NewCreate()
+	for _, tt := range tests {
+		got := common.Address(c.Address((*[20]byte)(&sender), tt.nonce))
+		if got.Hex() != tt.want {
+			t.Errorf("nonce %#x: got %s, want %s", tt.nonce, got.Hex(), tt.want)
+		}
+		if ref := gethcrypto.CreateAddress(sender, tt.nonce); got != ref {
+			t.Errorf("nonce %#x: got %s, go-ethereum gives %s", tt.nonce, got.Hex(), ref.Hex())
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package crypto
+
+// CosmosHRP is the human-readable part of Cosmos Hub accounts; other
+// Cosmos-SDK chains use their own ("osmo", "juno", …).
+const CosmosHRP = "cosmos"
+
+// AppendCosmosAddress appends the bech32 account address of a key hash, the
+// Hash160 of the compressed public key, as Cosmos-SDK chains encode it:
+// hrp + "1" + 32 data characters + checksum, with no witness version.
+func AppendCosmosAddress(dst []byte, hrp string, h *[20]byte) []byte {
+	var buf [32]byte
+	return appendBech32(dst, hrp, appendBase32(buf[:0], h[:]), bech32Const)
+}
@@ -0,0 +1,29 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestAppendCosmosAddress(t *testing.T) {
+	tests := []struct {
+		hrp, hash, want string
+	}{
+		// "abandon … about" at m/44'/118'/0'/0/0, the first Keplr account.
+		{CosmosHRP, "28ff5c6d57d8cfd492b6fb42614536ed648e01fd", "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4"},
+		// The private key 1.
+		{CosmosHRP, "751e76e8199196d454941c45d1b3a323f1433bd6", "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c"},
+		{"osmo", "751e76e8199196d454941c45d1b3a323f1433bd6", "osmo1w508d6qejxtdg4y5r3zarvary0c5xw7kjxy2e2"},
+	}
+	for _, tt := range tests {
+		b, _ := hex.DecodeString(tt.hash)
+		h := [20]byte(b)
+		if got := string(AppendCosmosAddress(nil, tt.hrp, &h)); got != tt.want {
+			t.Errorf("%s %s: got %s, want %s", tt.hrp, tt.hash, got, tt.want)
+		}
+	}
+
+	if h := KeyHash(keyOne(t)); string(AppendCosmosAddress(nil, CosmosHRP, &h)) != tests[1].want {
+		t.Errorf("key 1 does not hash to %s", tests[1].want)
+	}
+}
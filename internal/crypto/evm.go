@@ -16,6 +16,10 @@ func PrivToHex(priv *ecdsa.PrivateKey) string {
 	return "0x" + fmt.Sprintf("%x", gethcrypto.FromECDSA(priv))
 }
 
+func Address(priv *ecdsa.PrivateKey) [20]byte {
+	return gethcrypto.PubkeyToAddress(priv.PublicKey)
+}
+
 func AddressHex(priv *ecdsa.PrivateKey) string {
 	return gethcrypto.PubkeyToAddress(priv.PublicKey).Hex()
 }
@@ -0,0 +1,48 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"strconv"
+)
+
+// Solana keys are ed25519: the address is the base58 form of the 32-byte
+// public key itself, without version byte or checksum.
+
+// NewSolanaKey derives the ed25519 key of a 32-byte seed. The returned key
+// is seed ++ public key, the 64-byte secret that Solana wallets store.
+func NewSolanaKey(seed []byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// AppendSolanaAddress appends the address of an ed25519 public key: 32 to
+// 44 base58 characters.
+func AppendSolanaAddress(dst []byte, pub *[32]byte) []byte {
+	return appendBase58(dst, pub[:])
+}
+
+// SolanaAddress is the address of priv's public key.
+func SolanaAddress(priv ed25519.PrivateKey) string {
+	var buf [44]byte
+	return string(AppendSolanaAddress(buf[:0], (*[32]byte)(priv[32:])))
+}
+
+// SolanaSecret is the base58 form of the 64-byte secret, as Phantom and
+// other browser wallets import it.
+func SolanaSecret(priv ed25519.PrivateKey) string {
+	var buf [88]byte
+	return string(appendBase58(buf[:0], priv))
+}
+
+// SolanaKeypairJSON is the 64-byte secret as a JSON array of numbers, the
+// keypair file format of the Solana CLI (solana-keygen).
+func SolanaKeypairJSON(priv ed25519.PrivateKey) []byte {
+	b := make([]byte, 0, 4*len(priv)+2)
+	b = append(b, '[')
+	for i, c := range priv {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendUint(b, uint64(c), 10)
+	}
+	return append(b, ']')
+}
@@ -0,0 +1,33 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestSolanaKey(t *testing.T) {
+	// The key of RFC 8032, test 1.
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	k := NewSolanaKey(seed)
+	if got, want := hex.EncodeToString(k[32:]), "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"; got != want {
+		t.Fatalf("public key: got %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"address", SolanaAddress(k), "FVen3X669xLzsi6N2V91DoiyzHzg1uAgqiT8jZ9nS96Z"},
+		{"secret", SolanaSecret(k), "49W385L4rePHy6PAaQUovbD2aacgN4HsKXSMeUzRg4fmwXszN91JuMFrQRj3vMDpZuRF3ZknQBuRBoWQJEfXstMw"},
+		{"keypair", string(SolanaKeypairJSON(k[:2:2])), "[157,97]"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+
+	var zero [32]byte // the system program
+	if got, want := string(AppendSolanaAddress(nil, &zero)), "11111111111111111111111111111111"; got != want {
+		t.Errorf("zero key: got %s, want %s", got, want)
+	}
+}
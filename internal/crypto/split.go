@@ -0,0 +1,108 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Split-key search: the owner keeps a secret s and hands out only its public
+// key S = s·G. The search looks for a partial key k such that the address of
+// S + k·G matches; the final key (s+k) mod n is only ever assembled by the
+// owner with CombineSplitKey.
+
+// ParsePubKey parses a hex secp256k1 public key, compressed (33 bytes) or
+// uncompressed (65 bytes), with or without 0x.
+func ParsePubKey(s string) (*ecdsa.PublicKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+	pub, err := secp256k1.ParsePubKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+	return pub.ToECDSA(), nil
+}
+
+// PubKeyHex is the compressed hex form of the public key of priv, as accepted
+// by ParsePubKey.
+func PubKeyHex(priv *ecdsa.PrivateKey) string {
+	return "0x" + hex.EncodeToString(gethcrypto.CompressPubkey(&priv.PublicKey))
+}
+
+// NewSplitWalker walks the points origin + k·G, origin + (k+1)·G, … from a
+// random k, where origin is the public key of a secret the walker never
+// sees. Keys are recovered with PartialKey instead of PrivKey.
+func NewSplitWalker(origin *ecdsa.PublicKey, batch int) (*Walker, error) {
+	var o secp256k1.JacobianPoint
+	if o.X.SetByteSlice(origin.X.Bytes()) || o.Y.SetByteSlice(origin.Y.Bytes()) {
+		return nil, errors.New("origin is not a valid secp256k1 point")
+	}
+	o.Z.SetInt(1)
+
+	priv, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer priv.Zero()
+	w := newWalker(&priv.Key, 0, batch)
+	w.origin = &o
+
+	var p secp256k1.JacobianPoint
+	secp256k1.AddNonConst(&o, &w.point, &p)
+	if (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero() {
+		return nil, errors.New("walker base cancels the origin")
+	}
+	p.ToAffine()
+	w.point = p
+	return w, nil
+}
+
+// Split reports whether w walks relative to an origin point.
+func (w *Walker) Split() bool { return w.origin != nil }
+
+// PartialKey returns the partial key base+offset of a split walk after
+// checking that origin + (base+offset)·G has the address want.
+func (w *Walker) PartialKey(offset uint64, want [20]byte) ([32]byte, error) {
+	var k secp256k1.ModNScalar
+	k.Add2(&w.base, offsetScalar(offset))
+	kb := k.Bytes()
+	if k.IsZero() {
+		return kb, errors.New("walker reached the zero scalar")
+	}
+
+	var kG, p secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&k, &kG)
+	secp256k1.AddNonConst(w.origin, &kG, &p)
+	p.ToAffine()
+	pub := secp256k1.NewPublicKey(&p.X, &p.Y)
+	got := w.addressOf(pub.ToECDSA())
+	if got != want {
+		return kb, fmt.Errorf("walker key mismatch at offset %d: got 0x%x, want 0x%x", offset, got, want)
+	}
+	return kb, nil
+}
+
+// CombineSplitKey assembles the final key (secret+partial) mod n.
+func CombineSplitKey(secret *ecdsa.PrivateKey, partial []byte) (*ecdsa.PrivateKey, error) {
+	var s, k secp256k1.ModNScalar
+	if len(partial) != 32 || k.SetByteSlice(partial) {
+		return nil, errors.New("partial key is not a valid secp256k1 scalar")
+	}
+	sb := gethcrypto.FromECDSA(secret)
+	defer clear(sb)
+	s.SetByteSlice(sb)
+	s.Add(&k)
+	if s.IsZero() {
+		return nil, errors.New("combined key is zero")
+	}
+	fb := s.Bytes()
+	defer clear(fb[:])
+	return gethcrypto.ToECDSA(fb[:])
+}
@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// The partial keys of a split walk, combined with the owner's secret, open
+// the addresses the walk recorded.
+func TestSplitWalk(t *testing.T) {
+	secret, err := gethcrypto.ToECDSA(gethcrypto.Keccak256([]byte("split owner secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin, err := ParsePubKey(PubKeyHex(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if origin.X.Cmp(secret.X) != 0 || origin.Y.Cmp(secret.Y) != 0 {
+		t.Fatal("ParsePubKey(PubKeyHex) is not the public key")
+	}
+
+	for _, keyHash := range []bool{false, true} {
+		w, err := NewSplitWalker(origin, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keyHash {
+			w.UseKeyHash()
+		}
+		if !w.Split() {
+			t.Fatal("split walker does not report Split")
+		}
+		for range 2 {
+			first, addrs := w.Next()
+			for i, got := range addrs {
+				off := first + uint64(i)
+				partial, err := w.PartialKey(off, got)
+				if err != nil {
+					t.Fatalf("key hash %v, offset %d: %v", keyHash, off, err)
+				}
+				final, err := CombineSplitKey(secret, partial[:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				exp := Address(final)
+				if keyHash {
+					exp = KeyHash(final)
+				}
+				if exp != got {
+					t.Errorf("key hash %v, offset %d: combined key opens %x, walk recorded %x", keyHash, off, exp, got)
+				}
+			}
+		}
+
+		_, addrs := w.Next()
+		if _, err := w.PartialKey(0, addrs[1]); err == nil {
+			t.Errorf("key hash %v: PartialKey accepted the address of another offset", keyHash)
+		}
+		if _, err := w.PrivKey(0, addrs[0]); err == nil {
+			t.Errorf("key hash %v: split walker gave a private key", keyHash)
+		}
+	}
+}
+
+func TestCombineSplitKey(t *testing.T) {
+	one := keyOne(t)
+	n := gethcrypto.S256().Params().N
+
+	// 1 + (n-1) wraps to zero.
+	if _, err := CombineSplitKey(one, n.FillBytes(make([]byte, 32))); err == nil {
+		t.Error("partial key n accepted")
+	}
+	last := new(big.Int).Sub(n, big.NewInt(1)).FillBytes(make([]byte, 32))
+	if _, err := CombineSplitKey(one, last); err == nil {
+		t.Error("combined key zero accepted")
+	}
+
+	// 1 + 1 = 2.
+	partial := make([]byte, 32)
+	partial[31] = 1
+	k, err := CombineSplitKey(one, partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.D.Int64() != 2 {
+		t.Errorf("got %x, want 2", k.D)
+	}
+}
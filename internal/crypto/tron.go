@@ -0,0 +1,21 @@
+package crypto
+
+// TronVersion is the version byte of Tron mainnet addresses, which makes
+// them start with "T".
+const TronVersion = 0x41
+
+// AppendTronAddress appends the Tron form of an EVM address: the base58check
+// of 0x41 ++ the same 20 bytes. It does not allocate when dst has room for
+// the 34 characters.
+func AppendTronAddress(dst []byte, raw *[20]byte) []byte {
+	var payload [21]byte
+	payload[0] = TronVersion
+	copy(payload[1:], raw[:])
+	return appendBase58Check(dst, payload[:])
+}
+
+// TronAddress is the "T…" address of an EVM address.
+func TronAddress(raw *[20]byte) string {
+	var buf [34]byte
+	return string(AppendTronAddress(buf[:0], raw))
+}
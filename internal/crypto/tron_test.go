@@ -0,0 +1,39 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// keyOne is the private key 1, whose addresses are well known.
+func keyOne(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	k, err := gethcrypto.HexToECDSA("0000000000000000000000000000000000000000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestTronAddress(t *testing.T) {
+	a := Address(keyOne(t))
+	if got, want := TronAddress(&a), "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC"; got != want {
+		t.Errorf("key 1: got %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		evm, want string
+	}{
+		{"0x0000000000000000000000000000000000000000", "T9yD14Nj9j7xAB4dbGeiX9h8unkKHxuWwb"},
+		{"0xa614f803B6FD780986A42c78Ec9c7f77e6DeD13C", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"}, // USDT-TRC20
+	}
+	for _, tt := range tests {
+		raw := [20]byte(common.HexToAddress(tt.evm))
+		if got := TronAddress(&raw); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.evm, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultWalkBatch is the number of points converted to affine coordinates
+// with one shared field inversion.
+const DefaultWalkBatch = 256
+
+// Walker enumerates the consecutive private keys base, base+1, base+2, …
+// without a scalar multiplication per key: every public key is obtained from
+// the previous one by adding the generator point, and each batch of points is
+// converted to affine coordinates with a single shared inversion before
+// hashing.
+type Walker struct {
+	base  secp256k1.ModNScalar
+	point secp256k1.JacobianPoint // public key of base+next
+	next  uint64
+	g     secp256k1.JacobianPoint
+
+	origin *secp256k1.JacobianPoint // split walks only, see NewSplitWalker
+
+	pts   []secp256k1.JacobianPoint
+	acc   []secp256k1.FieldVal
+	addrs [][20]byte
+	buf   [64]byte
+	hash  gethcrypto.KeccakState
+
+	keyHash *hasher160 // set by UseKeyHash
+}
+
+// NewWalker starts a walk at the 32-byte big-endian scalar base.
+func NewWalker(base []byte, batch int) (*Walker, error) {
+	return NewWalkerAt(base, 0, batch)
+}
+
+// NewWalkerAt resumes a walk over base, base+1, … at base+offset. Offsets
+// passed to PrivKey stay relative to base.
+func NewWalkerAt(base []byte, offset uint64, batch int) (*Walker, error) {
+	var k secp256k1.ModNScalar
+	if len(base) != 32 || k.SetByteSlice(base) || k.IsZero() {
+		return nil, errors.New("walker base is not a valid secp256k1 scalar")
+	}
+	return newWalker(&k, offset, batch), nil
+}
+
+// NewRandomWalker starts a walk at a random base drawn from crypto/rand.
+func NewRandomWalker(batch int) (*Walker, error) {
+	priv, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer priv.Zero()
+	return newWalker(&priv.Key, 0, batch), nil
+}
+
+func newWalker(base *secp256k1.ModNScalar, offset uint64, batch int) *Walker {
+	if batch <= 0 {
+		batch = DefaultWalkBatch
+	}
+	w := &Walker{
+		pts:   make([]secp256k1.JacobianPoint, batch),
+		acc:   make([]secp256k1.FieldVal, batch),
+		addrs: make([][20]byte, batch),
+		hash:  gethcrypto.NewKeccakState(),
+	}
+	w.base.Set(base)
+	w.next = offset
+	var k secp256k1.ModNScalar
+	k.Add2(&w.base, offsetScalar(offset))
+	secp256k1.ScalarBaseMultNonConst(&k, &w.point)
+	w.point.ToAffine()
+
+	var one secp256k1.ModNScalar
+	one.SetInt(1)
+	secp256k1.ScalarBaseMultNonConst(&one, &w.g)
+	w.g.ToAffine()
+	return w
+}
+
+// UseKeyHash makes the walk yield key hashes, the Hash160 of the compressed
+// public keys that Bitcoin and Cosmos addresses encode, in place of EVM
+// addresses.
+func (w *Walker) UseKeyHash() { w.keyHash = newHasher160() }
+
+// addressOf is what the walk yields for pub.
+func (w *Walker) addressOf(pub *ecdsa.PublicKey) [20]byte {
+	if w.keyHash != nil {
+		return Hash160(gethcrypto.CompressPubkey(pub))
+	}
+	return gethcrypto.PubkeyToAddress(*pub)
+}
+
+// Next advances the walk by one batch. It returns the offset of the first key
+// of the batch and the addresses of the keys first, first+1, … The returned
+// slice is reused by the following call.
+func (w *Walker) Next() (uint64, [][20]byte) {
+	first := w.next
+	n := len(w.pts)
+
+	// Walk the points in Jacobian coordinates, accumulating the running
+	// product of their Z values for the batch inversion.
+	for i := 0; i < n; i++ {
+		w.pts[i].Set(&w.point)
+		if i == 0 {
+			w.acc[i].Set(&w.point.Z)
+		} else {
+			w.acc[i].Mul2(&w.acc[i-1], &w.point.Z)
+		}
+		secp256k1.AddNonConst(&w.pts[i], &w.g, &w.point)
+	}
+	w.next += uint64(n)
+
+	// inv = (Z_0 * … * Z_{n-1})^-1, then peel one Z off per step.
+	var inv, zInv, zInv2 secp256k1.FieldVal
+	inv.Set(&w.acc[n-1]).Inverse()
+	for i := n - 1; i >= 0; i-- {
+		if i > 0 {
+			zInv.Mul2(&inv, &w.acc[i-1])
+			inv.Mul(&w.pts[i].Z)
+		} else {
+			zInv.Set(&inv)
+		}
+		p := &w.pts[i]
+		zInv2.SquareVal(&zInv)
+		p.X.Mul(&zInv2).Normalize()
+		p.Y.Mul(zInv2.Mul(&zInv)).Normalize()
+
+		if w.keyHash != nil {
+			w.buf[0] = 0x02
+			if p.Y.IsOdd() {
+				w.buf[0] = 0x03
+			}
+			p.X.PutBytesUnchecked(w.buf[1:33])
+			w.keyHash.sum(w.buf[:33], &w.addrs[i])
+			continue
+		}
+		p.X.PutBytesUnchecked(w.buf[:32])
+		p.Y.PutBytesUnchecked(w.buf[32:])
+		var h [32]byte
+		w.hash.Reset()
+		w.hash.Write(w.buf[:])
+		w.hash.Read(h[:])
+		copy(w.addrs[i][:], h[12:])
+	}
+	return first, w.addrs
+}
+
+// PrivKey reconstructs the private key base+offset and re-derives its address,
+// failing unless it equals want.
+func (w *Walker) PrivKey(offset uint64, want [20]byte) (*ecdsa.PrivateKey, error) {
+	if w.Split() {
+		return nil, errors.New("split walker has no private keys, use PartialKey")
+	}
+	var k secp256k1.ModNScalar
+	k.Add2(&w.base, offsetScalar(offset))
+	if k.IsZero() {
+		return nil, errors.New("walker reached the zero scalar")
+	}
+	kb := k.Bytes()
+	priv, err := gethcrypto.ToECDSA(kb[:])
+	if err != nil {
+		return nil, err
+	}
+	got := w.addressOf(&priv.PublicKey)
+	if got != want {
+		return nil, fmt.Errorf("walker key mismatch at offset %d: got 0x%x, want 0x%x", offset, got, want)
+	}
+	return priv, nil
+}
+
+func offsetScalar(offset uint64) *secp256k1.ModNScalar {
+	var b [32]byte
+	binary.BigEndian.PutUint64(b[24:], offset)
+	var s secp256k1.ModNScalar
+	s.SetBytes(&b)
+	return &s
+}
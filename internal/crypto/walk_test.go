@@ -0,0 +1,141 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// walkBase is an arbitrary full-width scalar below the curve order.
+var walkBase = gethcrypto.Keccak256([]byte("walker test base"))
+
+// keyAt is the private key base+offset, computed with big integers.
+func keyAt(t *testing.T, base []byte, offset uint64) []byte {
+	t.Helper()
+	k := new(big.Int).SetBytes(base)
+	k.Add(k, new(big.Int).SetUint64(offset))
+	k.Mod(k, gethcrypto.S256().Params().N)
+	return k.FillBytes(make([]byte, 32))
+}
+
+// Every address of several batches is the one of the key base+offset by a
+// plain scalar multiplication, and PrivKey gives that key back.
+func TestWalkerNext(t *testing.T) {
+	for _, keyHash := range []bool{false, true} {
+		w, err := NewWalker(walkBase, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keyHash {
+			w.UseKeyHash()
+		}
+		var want uint64
+		for range 3 {
+			first, addrs := w.Next()
+			if first != want || len(addrs) != 7 {
+				t.Fatalf("key hash %v: got batch at %d of %d, want %d of 7", keyHash, first, len(addrs), want)
+			}
+			for i, got := range addrs {
+				off := first + uint64(i)
+				priv, err := gethcrypto.ToECDSA(keyAt(t, walkBase, off))
+				if err != nil {
+					t.Fatal(err)
+				}
+				exp := Address(priv)
+				if keyHash {
+					exp = KeyHash(priv)
+				}
+				if got != exp {
+					t.Errorf("key hash %v, offset %d: got %x, want %x", keyHash, off, got, exp)
+				}
+
+				k, err := w.PrivKey(off, got)
+				if err != nil {
+					t.Errorf("key hash %v, offset %d: %v", keyHash, off, err)
+				} else if k.D.Cmp(priv.D) != 0 {
+					t.Errorf("key hash %v, offset %d: PrivKey gives %x", keyHash, off, k.D)
+				}
+			}
+			want += 7
+		}
+	}
+}
+
+func TestWalkerPrivKeyMismatch(t *testing.T) {
+	w, err := NewWalker(walkBase, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, addrs := w.Next()
+	if _, err := w.PrivKey(1, addrs[2]); err == nil {
+		t.Error("PrivKey accepted the address of another offset")
+	}
+}
+
+// The walk steps over a carry into the high bytes of the scalar.
+func TestWalkerCarry(t *testing.T) {
+	base := make([]byte, 32)
+	for i := 8; i < 32; i++ {
+		base[i] = 0xff
+	}
+	w, err := NewWalker(base, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, addrs := w.Next()
+	for i, got := range addrs {
+		priv, err := gethcrypto.ToECDSA(keyAt(t, base, first+uint64(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := Address(priv); got != exp {
+			t.Errorf("offset %d: got %x, want %x", i, got, exp)
+		}
+	}
+}
+
+// A walk resumed at an offset yields what the full walk yields there, and
+// offsets stay relative to the base.
+func TestNewWalkerAt(t *testing.T) {
+	full, err := NewWalker(walkBase, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full.Next()
+	_, want := full.Next() // offsets 8..15
+
+	w, err := NewWalkerAt(walkBase, 8, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for b := range 2 {
+		first, addrs := w.Next()
+		if first != 8+uint64(b)*4 {
+			t.Fatalf("batch %d starts at %d", b, first)
+		}
+		for i, got := range addrs {
+			off := first + uint64(i)
+			if got != want[off-8] {
+				t.Errorf("offset %d: got %x, want %x", off, got, want[off-8])
+			}
+			if _, err := w.PrivKey(off, got); err != nil {
+				t.Errorf("offset %d: %v", off, err)
+			}
+		}
+	}
+}
+
+func TestNewWalkerAtRejects(t *testing.T) {
+	n := gethcrypto.S256().Params().N
+	for name, base := range map[string][]byte{
+		"zero":        make([]byte, 32),
+		"order":       n.FillBytes(make([]byte, 32)),
+		"above order": new(big.Int).Add(n, big.NewInt(5)).FillBytes(make([]byte, 32)),
+		"short":       walkBase[:31],
+	} {
+		if _, err := NewWalkerAt(base, 0, 4); err == nil {
+			t.Errorf("%s base accepted", name)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"WalletTools/internal/logsink"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/config"
+	"WalletTools/pkg/logx"
+)
+
+// BenchOptions controls a benchmark run.
+type BenchOptions struct {
+	LogsBase     string        // logs
+	PatternsPath string        // configs/patterns.yaml
+	Duration     time.Duration // per configuration, default 3s
+	Workers      []int         // worker counts to try; empty = 1, 2, 4, … NumCPU
+	DeriveN      []int         // mnemonic DeriveN values; empty = 1, 5, 20
+	CPUProfile   bool          // write cpu.pprof into the run directory
+}
+
+// BenchResult is the measured throughput of one configuration.
+type BenchResult struct {
+	Config   string  `json:"config"`
+	Workers  int     `json:"workers"`
+	Attempts uint64  `json:"attempts"`
+	Rate     float64 `json:"rate_addr_per_sec"`
+}
+
+// Benchmark runs workerPriv and workerMnemonic in several configurations for
+// a fixed time at each worker count. Results are written to
+// logs/benchmark/<DD.MM.YYYY>/benchmark_<HH-MM-SS>/results.jsonl; hits are
+// discarded.
+func Benchmark(ctx context.Context, bo BenchOptions) ([]BenchResult, error) {
+	cfg, err := config.Load(bo.PatternsPath)
+	if err != nil {
+		return nil, fmt.Errorf("load patterns: %w", err)
+	}
+	if bo.Duration <= 0 {
+		bo.Duration = 3 * time.Second
+	}
+	if len(bo.Workers) == 0 {
+		for n := 1; n < runtime.NumCPU(); n *= 2 {
+			bo.Workers = append(bo.Workers, n)
+		}
+		bo.Workers = append(bo.Workers, runtime.NumCPU())
+	}
+	if len(bo.DeriveN) == 0 {
+		bo.DeriveN = []int{1, 5, 20}
+	}
+
+	dir, err := logsink.MakeModuleDirs(bo.LogsBase, "benchmark", false)
+	if err != nil {
+		return nil, err
+	}
+	if err := logx.Init(logx.Config{Level: "info", FilePath: filepath.Join(dir, "app.log")}); err != nil {
+		return nil, fmt.Errorf("logx init for module failed: %w", err)
+	}
+	app := logx.S()
+
+	if bo.CPUProfile {
+		f, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+		if err != nil {
+			return nil, fmt.Errorf("create cpu profile: %w", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return nil, fmt.Errorf("start cpu profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	type benchConfig struct {
+		name string
+		opt  Options
+	}
+	var configs []benchConfig
+	for _, inc := range []bool{true, false} {
+		for _, enc := range []bool{false, true} {
+			configs = append(configs, benchConfig{
+				name: fmt.Sprintf("private incremental=%v encrypt=%v", inc, enc),
+				opt: Options{
+					Source:           SourcePrivKey,
+					Incremental:      inc,
+					Encrypt:          enc,
+					KeystorePassword: "benchmark",
+				},
+			})
+		}
+	}
+	for _, n := range bo.DeriveN {
+		configs = append(configs, benchConfig{
+			name: fmt.Sprintf("mnemonic derive_n=%d", n),
+			opt:  Options{Source: SourceMnemonic, WordsStrength: 128, DeriveN: n},
+		})
+	}
+
+	app.Infow("benchmark started",
+		"configs", len(configs),
+		"workers", bo.Workers,
+		"duration_per_config", bo.Duration.String(),
+		"out", dir,
+	)
+
+	var results []BenchResult
+	for _, c := range configs {
+		for _, w := range bo.Workers {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			// A fresh matcher per run: quotas must not carry over.
+			m, err := patterns.New(cfg)
+			if err != nil {
+				return results, fmt.Errorf("compile patterns: %w", err)
+			}
+			opt := c.opt
+			opt.Workers = w
+			n, elapsed, err := benchOne(ctx, opt, m, bo.Duration)
+			if err != nil {
+				return results, err
+			}
+			r := BenchResult{Config: c.name, Workers: w, Attempts: n, Rate: float64(n) / elapsed.Seconds()}
+			results = append(results, r)
+			app.Infow("benchmark",
+				"config", r.Config,
+				"workers", r.Workers,
+				"rate_addr_per_sec", fmt.Sprintf("%.2f", r.Rate),
+			)
+			b, _ := json.Marshal(r)
+			if err := appendJSONL(dir, "results", b); err != nil {
+				app.Errorw("jsonl append failed", "kind", "results", "err", err)
+			}
+		}
+	}
+
+	app.Infow("benchmark done", "recommended_workers", BestWorkers(results))
+	return results, nil
+}
+
+// benchOne runs opt for d and returns the attempts made.
+func benchOne(ctx context.Context, opt Options, m *patterns.Matcher, d time.Duration) (uint64, time.Duration, error) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(opt.Workers))
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	events := make(chan foundEvent, opt.Workers*4)
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for range events {
+		}
+	}()
+
+	var attempts uint64
+	var wg sync.WaitGroup
+	start := time.Now()
+	err := spawnWorkers(ctx, opt, m, &sourceState{}, start, &attempts, events, &wg)
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(events)
+	<-drained
+	return atomic.LoadUint64(&attempts), elapsed, err
+}
+
+// BestWorkers picks the worker count with the best throughput relative to the
+// best of each configuration, averaged over all configurations. Ties go to
+// fewer workers.
+func BestWorkers(results []BenchResult) int {
+	best := make(map[string]float64)
+	for _, r := range results {
+		if r.Rate > best[r.Config] {
+			best[r.Config] = r.Rate
+		}
+	}
+	score := make(map[int]float64)
+	for _, r := range results {
+		if best[r.Config] > 0 {
+			score[r.Workers] += r.Rate / best[r.Config]
+		}
+	}
+	bestW, bestScore := 0, -1.0
+	for w, sc := range score {
+		if sc > bestScore || sc == bestScore && w < bestW {
+			bestW, bestScore = w, sc
+		}
+	}
+	return bestW
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"WalletTools/internal/crypto"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/config"
+	"WalletTools/pkg/logx"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// wifNet is the network whose WIF keys results of format f carry, nil
+// outside Bitcoin.
+func wifNet(f config.AddressFormat) *crypto.BTCNet {
+	if !f.Bitcoin() {
+		return nil
+	}
+	return crypto.BTCNetwork(f.Net())
+}
+
+// workerTaproot walks the keys k, k+1, … from a random k by point addition,
+// like the incremental search, but without batching: every key costs a
+// scalar multiplication anyway, since the output key Q = P + t·G depends on
+// the tweak t of P itself. The internal key k is what wallets import.
+func workerTaproot(
+	ctx context.Context,
+	m *patterns.Matcher,
+	start time.Time,
+	attempts *uint64,
+	out chan<- foundEvent,
+) {
+	net := wifNet(m.Format())
+	base, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		logx.S().Errorw("generate priv failed", "err", err)
+		return
+	}
+	defer base.Zero()
+	k := base.Key
+
+	var one secp256k1.ModNScalar
+	one.SetInt(1)
+	var p, g, next secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&k, &p)
+	p.ToAffine()
+	secp256k1.ScalarBaseMultNonConst(&one, &g)
+	g.ToAffine()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		n := atomic.AddUint64(attempts, 1)
+		q, err := crypto.TaprootOutputKey(secp256k1.NewPublicKey(&p.X, &p.Y))
+		if err == nil {
+			if mr := m.MatchPayload(q[:]); mr != nil {
+				if ev, err := taprootHit(&k, &q, net, m.Format()); err != nil {
+					logx.S().Errorw("taproot key verification failed", "err", err)
+				} else {
+					ev.Kind, ev.Match, ev.Final = mr.Kind, mr, mr.Final
+					ev.Elapsed, ev.Attempt = time.Since(start), n
+					select {
+					case <-ctx.Done():
+						return
+					case out <- ev:
+					}
+				}
+			}
+		}
+
+		k.Add(&one)
+		secp256k1.AddNonConst(&p, &g, &next)
+		next.ToAffine()
+		p = next
+	}
+}
+
+// taprootHit re-derives the output key of k from scratch before the key is
+// handed out.
+func taprootHit(k *secp256k1.ModNScalar, want *[32]byte, net *crypto.BTCNet, f config.AddressFormat) (foundEvent, error) {
+	kb := k.Bytes()
+	defer clear(kb[:])
+	priv, err := gethcrypto.ToECDSA(kb[:])
+	if err != nil {
+		return foundEvent{}, err
+	}
+	pub, err := secp256k1.ParsePubKey(gethcrypto.CompressPubkey(&priv.PublicKey))
+	if err != nil {
+		return foundEvent{}, err
+	}
+	q, err := crypto.TaprootOutputKey(pub)
+	if err != nil {
+		return foundEvent{}, err
+	}
+	if q != *want {
+		return foundEvent{}, errors.New("output key mismatch")
+	}
+	return foundEvent{
+		Address:    patterns.Render(f, q[:]),
+		PrivateHex: crypto.PrivToHex(priv),
+		WIF:        crypto.WIF(priv, net),
+	}, nil
+}
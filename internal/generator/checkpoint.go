@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethks "github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	checkpointFile  = "checkpoint.json"
+	checkpointEvery = 30 * time.Second
+)
+
+// checkpoint is the resumable state of a seeded search. Range i walks the
+// keys start_i, start_i+1, … where start_i is derived from the seed, and
+// Done[i] keys of it have been fully tested and their hits written. The seed
+// is only ever stored encrypted with the seed password.
+type checkpoint struct {
+	Version  int               `json:"version"`
+	Seed     gethks.CryptoJSON `json:"seed"`
+	Done     []uint64          `json:"done"`
+	Patterns string            `json:"patterns_sha256"`
+	Updated  string            `json:"updated"`
+
+	done []atomic.Uint64 // live counters, one per range
+	seed []byte
+	path string
+}
+
+// newCheckpoint draws a fresh seed for ranges ranges and writes the
+// checkpoint into dir.
+func newCheckpoint(dir string, ranges int, password string, patternsSum string) (*checkpoint, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	enc, err := gethks.EncryptDataV3(seed, []byte(password), gethks.StandardScryptN, gethks.StandardScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt seed: %w", err)
+	}
+	ck := &checkpoint{
+		Version:  1,
+		Seed:     enc,
+		Done:     make([]uint64, ranges),
+		Patterns: patternsSum,
+		done:     make([]atomic.Uint64, ranges),
+		seed:     seed,
+		path:     filepath.Join(dir, checkpointFile),
+	}
+	return ck, ck.save()
+}
+
+// loadCheckpoint reads the checkpoint of an interrupted run in dir and
+// decrypts its seed.
+func loadCheckpoint(dir, password string) (*checkpoint, error) {
+	path := filepath.Join(dir, checkpointFile)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	var ck checkpoint
+	if err := json.Unmarshal(b, &ck); err != nil {
+		return nil, fmt.Errorf("decode checkpoint %q: %w", path, err)
+	}
+	if ck.Version != 1 || len(ck.Done) == 0 {
+		return nil, fmt.Errorf("unsupported checkpoint %q", path)
+	}
+	seed, err := gethks.DecryptDataV3(ck.Seed, password)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt seed: %w", err)
+	}
+	ck.seed = seed
+	ck.path = path
+	ck.done = make([]atomic.Uint64, len(ck.Done))
+	for i, d := range ck.Done {
+		ck.done[i].Store(d)
+	}
+	return &ck, nil
+}
+
+// ResumePatternsChanged reports whether the patterns file has changed since
+// the checkpoint in dir was written. It needs no seed password.
+func ResumePatternsChanged(dir, patternsPath string) (bool, error) {
+	path := filepath.Join(dir, checkpointFile)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("read checkpoint: %w", err)
+	}
+	var ck checkpoint
+	if err := json.Unmarshal(b, &ck); err != nil {
+		return false, fmt.Errorf("decode checkpoint %q: %w", path, err)
+	}
+	sum, err := fileSHA256(patternsPath)
+	if err != nil {
+		return false, fmt.Errorf("hash patterns: %w", err)
+	}
+	return sum != ck.Patterns, nil
+}
+
+// rangeStart is the first key of range i: keccak256(seed || i) reduced
+// modulo the curve order, since the walker rejects scalars that overflow it.
+func (ck *checkpoint) rangeStart(i int) []byte {
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], uint32(i))
+	var k secp256k1.ModNScalar
+	k.SetByteSlice(gethcrypto.Keccak256(ck.seed, idx[:]))
+	b := k.Bytes()
+	return b[:]
+}
+
+// snapshot copies the tested-key counters for a later save.
+func (ck *checkpoint) snapshot() []uint64 {
+	out := make([]uint64, len(ck.done))
+	for i := range ck.done {
+		out[i] = ck.done[i].Load()
+	}
+	return out
+}
+
+// saveSnapshot atomically replaces the checkpoint file with done.
+func (ck *checkpoint) saveSnapshot(done []uint64) error {
+	ck.Done = done
+	return ck.save()
+}
+
+func (ck *checkpoint) save() error {
+	ck.Updated = time.Now().Format(time.RFC3339)
+	b, err := json.MarshalIndent(ck, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := ck.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ck.path)
+}
+
+// tested is the number of keys tested over all ranges.
+func (ck *checkpoint) tested(done []uint64) uint64 {
+	var n uint64
+	for _, d := range done {
+		n += d
+	}
+	return n
+}
+
+func fileSHA256(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+var (
+	errNoSeedPassword  = errors.New("seeded search requires a seed password")
+	errSeededSource    = errors.New("seeded search supports the private key and CREATE sources only")
+	errPatternsChanged = errors.New("patterns changed since the checkpoint: keys tested before would not be checked against them; resume with ResumeChangedPatterns to accept that")
+)
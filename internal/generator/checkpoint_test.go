@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"WalletTools/internal/crypto"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	ck, err := newCheckpoint(dir, 3, "seed password", "patterns-sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ck.done[0].Store(4096)
+	ck.done[2].Store(17)
+	if err := ck.saveSnapshot(ck.snapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadCheckpoint(dir, "seed password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.seed, ck.seed) || got.Patterns != "patterns-sum" {
+		t.Errorf("got seed %x patterns %q, want %x %q", got.seed, got.Patterns, ck.seed, "patterns-sum")
+	}
+	if d := got.snapshot(); len(d) != 3 || d[0] != 4096 || d[1] != 0 || d[2] != 17 {
+		t.Errorf("got done %v, want [4096 0 17]", d)
+	}
+	if got.tested(got.Done) != 4113 {
+		t.Errorf("got %d tested, want 4113", got.tested(got.Done))
+	}
+	for i := range 3 {
+		if !bytes.Equal(got.rangeStart(i), ck.rangeStart(i)) {
+			t.Errorf("range %d starts elsewhere after loading", i)
+		}
+		if _, err := crypto.NewWalkerAt(got.rangeStart(i), got.done[i].Load(), 4); err != nil {
+			t.Errorf("range %d: %v", i, err)
+		}
+	}
+	if bytes.Equal(ck.rangeStart(0), ck.rangeStart(1)) {
+		t.Error("ranges 0 and 1 start at the same key")
+	}
+
+	if _, err := loadCheckpoint(dir, "wrong password"); err == nil {
+		t.Error("checkpoint loaded with a wrong password")
+	}
+	if _, err := loadCheckpoint(t.TempDir(), "seed password"); err == nil {
+		t.Error("checkpoint loaded from an empty directory")
+	}
+}
+
+func TestResumePatternsChanged(t *testing.T) {
+	dir := t.TempDir()
+	patterns := filepath.Join(dir, "patterns.yaml")
+	if err := os.WriteFile(patterns, []byte("version: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := fileSHA256(patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newCheckpoint(dir, 1, "seed password", sum); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := ResumePatternsChanged(dir, patterns); err != nil || changed {
+		t.Errorf("same patterns: got %v, %v", changed, err)
+	}
+	if err := os.WriteFile(patterns, []byte("version: 2\nsymbols: \"0 1\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := ResumePatternsChanged(dir, patterns); err != nil || !changed {
+		t.Errorf("edited patterns: got %v, %v", changed, err)
+	}
+}
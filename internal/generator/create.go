@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"WalletTools/internal/crypto"
+	"WalletTools/internal/patterns"
+)
+
+// creator matches the CREATE addresses of a deployer key for the nonces
+// 0..maxNonce instead of the key's own address. A nil creator matches the
+// address itself. Each worker needs its own.
+type creator struct {
+	c        *crypto.Create
+	maxNonce uint64
+}
+
+func newCreator(opt Options) *creator {
+	if opt.Source != SourceCreate {
+		return nil
+	}
+	return &creator{c: crypto.NewCreate(), maxNonce: opt.CreateMaxNonce}
+}
+
+// perKey is the number of addresses matched per key.
+func (cr *creator) perKey() uint64 {
+	if cr == nil {
+		return 1
+	}
+	return cr.maxNonce + 1
+}
+
+// match returns the first matching address derived from the key address eoa
+// and the nonce that produced it.
+func (cr *creator) match(m *patterns.Matcher, eoa *[20]byte) (*patterns.MatchResult, [20]byte, uint64) {
+	if cr == nil {
+		return m.MatchRaw(eoa), *eoa, 0
+	}
+	for nonce := uint64(0); nonce <= cr.maxNonce; nonce++ {
+		a := cr.c.Address(eoa, nonce)
+		if mr := m.MatchRaw(&a); mr != nil {
+			return mr, a, nonce
+		}
+	}
+	return nil, [20]byte{}, 0
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"WalletTools/internal/crypto"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/logx"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// create2Batch is how many salts a worker tries between two updates of the
+// shared attempts counter.
+const create2Batch = 1024
+
+type create2Target struct {
+	deployer     common.Address
+	initCodeHash common.Hash
+}
+
+func parseCreate2(deployer, initCodeHash string) (*create2Target, error) {
+	if !common.IsHexAddress(deployer) {
+		return nil, errors.New("create2: deployer is not a hex address")
+	}
+	h, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(initCodeHash), "0x"))
+	if err != nil || len(h) != common.HashLength {
+		return nil, errors.New("create2: init code hash must be 32 bytes of hex")
+	}
+	return &create2Target{
+		deployer:     common.HexToAddress(deployer),
+		initCodeHash: common.BytesToHash(h),
+	}, nil
+}
+
+// workerCreate2 tries the salts r, r+1, r+2… from a random 32-byte r; the
+// counter lives in the last 8 bytes, so workers never overlap in practice.
+func workerCreate2(
+	ctx context.Context,
+	m *patterns.Matcher,
+	t *create2Target,
+	start time.Time,
+	attempts *uint64,
+	out chan<- foundEvent,
+) {
+	var salt [32]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		logx.S().Errorw("create2 salt init failed", "err", err)
+		return
+	}
+	c := crypto.NewCreate2(t.deployer, t.initCodeHash)
+	ctr := binary.BigEndian.Uint64(salt[24:])
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		n := atomic.AddUint64(attempts, create2Batch) - create2Batch
+		for i := uint64(0); i < create2Batch; i++ {
+			binary.BigEndian.PutUint64(salt[24:], ctr)
+			ctr++
+			raw := c.Address(&salt)
+			mr := m.MatchRaw(&raw)
+			if mr == nil {
+				continue
+			}
+
+			ev := foundEvent{
+				Kind:    mr.Kind,
+				Address: common.Address(raw).Hex(),
+				Salt:    "0x" + hex.EncodeToString(salt[:]),
+				Elapsed: time.Since(start),
+				Attempt: n + i + 1,
+				Final:   mr.Final,
+				Match:   mr,
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- ev:
+			}
+		}
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"WalletTools/internal/keystore"
 	"WalletTools/internal/logsink"
 	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
 	"runtime"
 	"sync"
@@ -17,44 +21,239 @@ import (
 	"WalletTools/internal/patterns"
 	"WalletTools/pkg/config"
 	"WalletTools/pkg/logx"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type logPriv struct {
 	Address    string `json:"address"`
+	EVMAddress string `json:"evm_address,omitempty"`
 	PrivateKey string `json:"private_key,omitempty"`
+	WIF        string `json:"wif,omitempty"`
 	Keystore   string `json:"keystore,omitempty"`
 	Note       string `json:"note,omitempty"`
+
+	// Solana: the 64-byte secret in base58 and as the CLI keypair file.
+	SecretKey string          `json:"secret_key,omitempty"`
+	Keypair   json.RawMessage `json:"keypair,omitempty"`
+
+	Word *logWord `json:"word,omitempty"`
+}
+
+// logWord is the dictionary word of a words hit and its offset in the
+// address body.
+type logWord struct {
+	Text string `json:"text"`
+	Pos  int    `json:"pos"`
+}
+
+func wordOf(mr *patterns.MatchResult) *logWord {
+	if mr == nil || mr.Word == "" {
+		return nil
+	}
+	return &logWord{Text: mr.Word, Pos: mr.Pos}
+}
+
+type logSplit struct {
+	Address    string   `json:"address"`
+	EVMAddress string   `json:"evm_address,omitempty"`
+	PartialKey string   `json:"partial_key"`
+	PublicKey  string   `json:"public_key"`
+	Word       *logWord `json:"word,omitempty"`
+}
+
+type logCreate2 struct {
+	Address      string   `json:"address"`
+	Salt         string   `json:"salt"`
+	Deployer     string   `json:"deployer"`
+	InitCodeHash string   `json:"init_code_hash"`
+	Word         *logWord `json:"word,omitempty"`
+}
+
+// logCreate is a CREATE hit: the contract address, and the deployer key with
+// the nonce of the deployment that lands on it.
+type logCreate struct {
+	Address    string          `json:"address"`
+	Deployer   string          `json:"deployer"`
+	Nonce      uint64          `json:"nonce"`
+	PrivateKey string          `json:"private_key,omitempty"`
+	Keystore   json.RawMessage `json:"keystore,omitempty"`
+	Word       *logWord        `json:"word,omitempty"`
 }
 
 type foundEvent struct {
 	Kind       string
-	Address    string
+	Address    string // in the run's address format
+	EVMAddress string // set when that format is not EVM
 	PrivateHex string
+	WIF        string // Bitcoin formats
+	SecretKey  string // Solana, base58
+	Keypair    []byte // Solana, JSON array
 	KsJSON     []byte
 	Note       string
 	Elapsed    time.Duration
 	Attempt    uint64
 	Final      bool
+	Match      *patterns.MatchResult
+
+	Salt string
+
+	Deployer string
+	Nonce    uint64
 
 	Mnemonic string
 	Pass     string
+	Template string
 	Path     string
+	Account  int
 	Index    int
 }
 
 func Run(ctx context.Context, opt Options) error {
-	cfg, err := config.Load(opt.PatternsPath)
+	if opt.Format == "" {
+		opt.Format = config.FormatEVM
+	}
+	if opt.Format != config.FormatEVM && (opt.Source == SourceCreate || opt.Source == SourceCreate2) {
+		return fmt.Errorf("%s mode searches EVM contract addresses; %s derives them differently", opt.Source, opt.Format)
+	}
+	if opt.Format.Bitcoin() {
+		switch {
+		case opt.Source != SourcePrivKey:
+			return fmt.Errorf("%s addresses are only searched in %s mode", opt.Format, SourcePrivKey)
+		case opt.Encrypt:
+			return errors.New("keystores hold EVM keys; Bitcoin results carry the key as WIF instead")
+		case opt.Seeded && opt.Format.Script() == "p2tr":
+			return errors.New("seeded search does not support taproot addresses")
+		}
+	}
+	if opt.Format == config.FormatSolana {
+		switch {
+		case opt.Source != SourcePrivKey:
+			return fmt.Errorf("%s addresses are only searched in %s mode", opt.Format, SourcePrivKey)
+		case opt.Encrypt:
+			return errors.New("keystores hold EVM keys; Solana results carry the keypair instead")
+		case opt.Seeded:
+			return errors.New("seeded search walks secp256k1 keys and does not support Solana")
+		}
+		// ed25519 scalars are hashed from their seeds: there is nothing
+		// to walk, every key is drawn afresh.
+		opt.Incremental = false
+	}
+	if opt.Format.Script() == "cosmos" {
+		switch {
+		case opt.Source != SourcePrivKey && opt.Source != SourceMnemonic:
+			return fmt.Errorf("%s addresses are only searched in %s and %s mode", opt.Format, SourcePrivKey, SourceMnemonic)
+		case opt.Encrypt:
+			return errors.New("keystores hold EVM keys; Cosmos results carry the raw private key instead")
+		}
+		if opt.Source == SourceMnemonic && len(opt.DerivePaths) == 0 {
+			ct, ok := opt.Format.CoinType()
+			if !ok {
+				return fmt.Errorf("coin type of %s1… addresses is not known; give the derivation path of the chain's wallets", opt.Format.HRP())
+			}
+			opt.DerivePaths = []string{fmt.Sprintf("m/44'/%d'/0'/0/{i}", ct)}
+		}
+	}
+	cfg, err := config.LoadFor(opt.PatternsPath, opt.Format)
 	if err != nil {
 		return fmt.Errorf("load patterns: %w", err)
 	}
 
+	matcher, err := patterns.New(cfg)
+	if err != nil {
+		return fmt.Errorf("compile patterns: %w", err)
+	}
+
+	var splitPub *ecdsa.PublicKey
+	if opt.Source == SourceSplitKey {
+		if splitPub, err = crypto.ParsePubKey(opt.SplitPubKey); err != nil {
+			return err
+		}
+	}
+
+	var wl *mnemonic.Wordlist
+	var mstats *mnemonicStats
+	if opt.Source == SourceMnemonic {
+		mstats = &mnemonicStats{}
+		if opt.DerivePaths, err = mnemonic.ParsePathTemplates(opt.DerivePaths); err != nil {
+			return err
+		}
+		if opt.WordsStrength == 0 {
+			opt.WordsStrength = 128
+		}
+		if !mnemonic.ValidStrength(opt.WordsStrength) {
+			return fmt.Errorf("invalid mnemonic strength %d", opt.WordsStrength)
+		}
+		if wl, err = mnemonic.NewWordlist(opt.WordsLang); err != nil {
+			return err
+		}
+		opt.WordsLang = wl.Lang
+	}
+
+	// The mnemonic section only shapes the phrases of mnemonic mode.
+	var sampler *mnemonic.Sampler
+	switch {
+	case cfg.Mnemonic == nil:
+	case opt.Source == SourceMnemonic:
+		if sampler, err = wl.NewSampler(opt.WordsStrength, cfg.Mnemonic); err != nil {
+			return err
+		}
+	case !cfg.HasAddressPatterns():
+		return fmt.Errorf("patterns config has only a mnemonic section, which applies to %s mode only", SourceMnemonic)
+	}
+
+	var pg *passGen
+	if opt.Source == SourcePassphrase {
+		mstats = &mnemonicStats{}
+		if opt.DerivePaths, err = mnemonic.ParsePathTemplates(opt.DerivePaths); err != nil {
+			return err
+		}
+		if pg, err = newPassGen(opt); err != nil {
+			return err
+		}
+	}
+
+	var c2 *create2Target
+	if opt.Source == SourceCreate2 {
+		if c2, err = parseCreate2(opt.Create2Deployer, opt.Create2InitCodeHash); err != nil {
+			return err
+		}
+	}
+
+	// The wallet of a fixed mnemonic is the user's own: keep the phrase and
+	// its passphrase out of the masked console whatever the field. A short
+	// passphrase is not registered and stays masked by its field key only.
+	if opt.FixedMnemonic != "" {
+		logx.AddSecret(opt.FixedMnemonic)
+		logx.AddSecret(opt.Passphrase)
+		defer logx.ClearSecrets()
+	}
+
+	var scan *scanRange
+	if opt.Source == SourceScan {
+		if scan, err = newScanRange(opt); err != nil {
+			return err
+		}
+	}
+
 	module := string(opt.Source)
 	keystoreUsage := opt.Source == SourcePrivKey && opt.Encrypt
 
-	// logs/<module>/<DD.MM.YYYY>/<module_<HH-MM-SS>>
-	dir, err := logsink.MakeModuleDirs(opt.LogsBase, module, keystoreUsage)
-	if err != nil {
-		return err
+	if opt.Seeded && opt.Source != SourcePrivKey && opt.Source != SourceCreate {
+		return errSeededSource
+	}
+	if opt.Seeded && opt.SeedPassword == "" {
+		return errNoSeedPassword
+	}
+
+	// logs/<module>/<DD.MM.YYYY>/<module_<HH-MM-SS>>; a resumed seeded
+	// search continues in the directory of the interrupted run.
+	dir := opt.ResumeDir
+	if dir == "" {
+		dir, err = logsink.MakeModuleDirs(opt.LogsBase, module, keystoreUsage)
+		if err != nil {
+			return err
+		}
 	}
 	_ = logsink.WriteHint(dir, opt.PassHint)
 
@@ -72,84 +271,350 @@ func Run(ctx context.Context, opt Options) error {
 
 	// workers
 	workers := opt.Workers
-	runtime.GOMAXPROCS(workers)
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+		opt.Workers = workers
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(workers))
+
+	var ck *checkpoint
+	if opt.Seeded {
+		sum, err := fileSHA256(opt.PatternsPath)
+		if err != nil {
+			return fmt.Errorf("hash patterns: %w", err)
+		}
+		if opt.ResumeDir != "" {
+			ck, err = loadCheckpoint(dir, opt.SeedPassword)
+			if err != nil {
+				return err
+			}
+			if sum != ck.Patterns {
+				if !opt.ResumeChangedPatterns {
+					return errPatternsChanged
+				}
+				app.Warnw("patterns changed since the checkpoint; keys tested before were not checked against the new patterns",
+					"patterns", opt.PatternsPath)
+			}
+			workers = len(ck.Done)
+			opt.Workers = workers
+			runtime.GOMAXPROCS(workers)
+			app.Infow("seeded search resumed", "ranges", workers, "tested", ck.tested(ck.Done))
+		} else {
+			ck, err = newCheckpoint(dir, workers, opt.SeedPassword, sum)
+			if err != nil {
+				return err
+			}
+			app.Infow("seeded search started", "ranges", workers, "checkpoint", ck.path)
+		}
+	}
 
 	app.Infow("generation started",
 		"module", module,
 		"keystoreUsage", keystoreUsage,
+		"incremental", (opt.Source == SourcePrivKey || opt.Source == SourceCreate) && opt.Incremental,
+		"derive_paths", opt.DerivePaths,
+		"words", opt.WordsStrength/32*3,
+		"wordlist", opt.WordsLang,
+		"seeded", opt.Seeded,
 		"patterns", opt.PatternsPath,
+		"format", opt.Format,
 		"workers", workers,
 		"GOMAXPROCS", workers,
 	)
 
+	if scan != nil {
+		app.Infow("scan range",
+			"path", scan.template,
+			"accounts", scan.accounts,
+			"indexes", scan.indexes,
+			"total", scan.total(),
+			"wordlist", scan.lang,
+			"first_address", scan.first,
+		)
+	}
+
+	if sampler != nil {
+		app.Infow("mnemonic constraints",
+			"entropy_bits", fmt.Sprintf("%.1f", sampler.Bits()),
+			"loss_bits", fmt.Sprintf("%.1f", float64(sampler.Strength())-sampler.Bits()),
+			"standalone", !cfg.HasAddressPatterns(),
+		)
+		if cfg.Mnemonic.AllowWeak && sampler.Bits() < mnemonic.MinBits {
+			app.Warnw("mnemonic constraints weaken the wallets below the minimum, allowed by allow_weak",
+				"entropy_bits", fmt.Sprintf("%.1f", sampler.Bits()), "min_bits", mnemonic.MinBits)
+		}
+	} else if cfg.Mnemonic != nil {
+		app.Warnw("mnemonic section ignored outside mnemonic mode", "module", module)
+	}
+
+	if pg != nil {
+		app.Infow("passphrase search",
+			"wordlist", pg.lang,
+			"diceware", pg.sep != "",
+			"length", pg.length,
+			"alphabet", len(pg.alphabet),
+			"bits", fmt.Sprintf("%.1f", pg.bits()),
+		)
+	}
+
+	ests := matcher.Estimates()
+	for _, e := range ests {
+		fields := []any{
+			"pattern", e.Label(),
+			"probability", probString(e),
+			"expected_attempts", atLeast(e, fmt.Sprintf("%.4g", e.Expected())),
+			"p50_attempts", atLeast(e, fmt.Sprintf("%.4g", e.Attempts(0.5))),
+			"p90_attempts", atLeast(e, fmt.Sprintf("%.4g", e.Attempts(0.9))),
+		}
+		if e.CaseBits > 0 {
+			// What the EIP-55 casing adds over a case-insensitive search.
+			fields = append(fields, "checksum_case_cost", fmt.Sprintf("x%.4g (%d letters)", math.Exp2(float64(e.CaseBits)), e.CaseBits))
+		}
+		app.Infow("pattern difficulty", fields...)
+	}
+	app.Infow("pattern difficulty", "pattern", "any", "probability", probString(patterns.Combined(ests)))
+
 	start := time.Now()
 	showSecrets := !opt.CaseMaskedOut
 
 	events := make(chan foundEvent, workers*4)
 
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	var attempts uint64
 
-	var finalOnce sync.Once
-	writerDone := make(chan struct{})
-	go func() {
-		defer close(writerDone)
-		for ev := range events {
-			switch {
-			case opt.Source == SourcePrivKey && opt.Encrypt:
-				if err := appendJSONL(dir, ev.Kind, ev.KsJSON); err != nil {
-					logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
-				}
-			case opt.Source == SourcePrivKey && !opt.Encrypt:
-				rec := logPriv{Address: ev.Address, PrivateKey: ev.PrivateHex}
-				b, _ := json.Marshal(rec)
-				if err := appendJSONL(dir, ev.Kind, b); err != nil {
-					logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
-				}
-			case opt.Source == SourceMnemonic:
-				line := fmt.Sprintf(
-					"address=%s index=%d path=%s mnemonic=%q passphrase=%q priv=%s",
-					ev.Address, ev.Index, ev.Path, ev.Mnemonic, ev.Pass, ev.PrivateHex,
-				)
-				_ = logsink.WriteMatch(dir, ev.Kind, line, false)
+	// stop ends the run once; the first reason wins and goes to the summary.
+	var stopOnce sync.Once
+	var stopReason string
+	stop := func(reason string) {
+		stopOnce.Do(func() {
+			stopReason = reason
+			logx.S().Infow("stop condition reached, stop all workers", "reason", reason)
+			cancel()
+		})
+	}
+
+	if opt.MaxDuration > 0 {
+		t := time.AfterFunc(opt.MaxDuration, func() { stop("max duration") })
+		defer t.Stop()
+	}
+
+	hits := make(map[string]int)
+	totalHits := 0
+	handle := func(ev foundEvent) {
+		// Workers may overshoot a quota before it is disabled; drop
+		// those results instead of writing them.
+		label := ev.Match.Label()
+		if ev.Match.MaxHits > 0 && hits[label] >= ev.Match.MaxHits {
+			return
+		}
+		if opt.MaxHits > 0 && totalHits >= opt.MaxHits {
+			return
+		}
+		hits[label]++
+		totalHits++
+		word := wordOf(ev.Match)
+		if opt.Source == SourcePassphrase {
+			// Only reported passphrases are secrets worth masking.
+			logx.AddSecret(ev.Pass)
+		}
+
+		switch {
+		case opt.Source == SourcePrivKey && opt.Encrypt:
+			if err := appendJSONL(dir, ev.Kind, ev.KsJSON); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
+			}
+		case opt.Source == SourcePrivKey && !opt.Encrypt:
+			rec := logPriv{Address: ev.Address, EVMAddress: ev.EVMAddress, PrivateKey: ev.PrivateHex, WIF: ev.WIF, SecretKey: ev.SecretKey, Keypair: ev.Keypair, Word: word}
+			b, _ := json.Marshal(rec)
+			if err := appendJSONL(dir, ev.Kind, b); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
+			}
+		case opt.Source == SourceSplitKey:
+			rec := logSplit{Address: ev.Address, EVMAddress: ev.EVMAddress, PartialKey: ev.PrivateHex, PublicKey: opt.SplitPubKey, Word: word}
+			b, _ := json.Marshal(rec)
+			if err := appendJSONL(dir, ev.Kind, b); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
+			}
+		case opt.Source == SourceCreate:
+			rec := logCreate{Address: ev.Address, Deployer: ev.Deployer, Nonce: ev.Nonce, PrivateKey: ev.PrivateHex, Keystore: ev.KsJSON, Word: word}
+			b, _ := json.Marshal(rec)
+			if err := appendJSONL(dir, ev.Kind, b); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
+			}
+		case opt.Source == SourceCreate2:
+			rec := logCreate2{Address: ev.Address, Salt: ev.Salt, Deployer: c2.deployer.Hex(), InitCodeHash: c2.initCodeHash.Hex(), Word: word}
+			b, _ := json.Marshal(rec)
+			if err := appendJSONL(dir, ev.Kind, b); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
+			}
+		case opt.Source == SourceMnemonic:
+			line := fmt.Sprintf(
+				"address=%s template=%s index=%d path=%s words=%d lang=%s mnemonic=%q passphrase=%q priv=%s",
+				ev.Address, ev.Template, ev.Index, ev.Path, opt.WordsStrength/32*3, opt.WordsLang, ev.Mnemonic, ev.Pass, ev.PrivateHex,
+			)
+			if ev.EVMAddress != "" {
+				line += " evm=" + ev.EVMAddress
+			}
+			if word != nil {
+				line += fmt.Sprintf(" word=%s word_pos=%d", word.Text, word.Pos)
+			}
+			_ = logsink.WriteMatch(dir, ev.Kind, line, false)
+		case opt.Source == SourcePassphrase:
+			rec := logPassphrase{Address: ev.Address, EVMAddress: ev.EVMAddress, Passphrase: ev.Pass, Path: ev.Path, Word: word}
+			b, _ := json.Marshal(rec)
+			if err := appendJSONL(dir, ev.Kind, b); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
+			}
+		case opt.Source == SourceScan:
+			rec := logScan{Address: ev.Address, EVMAddress: ev.EVMAddress, Path: ev.Path, Account: ev.Account, Index: ev.Index, Word: word}
+			b, _ := json.Marshal(rec)
+			if err := appendJSONL(dir, ev.Kind, b); err != nil {
+				logx.S().Errorw("jsonl append failed", "addr", ev.Address, "kind", ev.Kind, "err", err)
 			}
+		}
 
+		// Keystore records stay plain keystores: the word of an encrypted
+		// hit is only in the FOUND line.
+		found := func(fields ...any) {
+			if word != nil {
+				fields = append(fields, "word", word.Text, "word_pos", word.Pos)
+			}
+			logx.S().Infow("FOUND", fields...)
+		}
+
+		switch {
+		case opt.Source == SourceCreate2:
+			// A salt is no secret: without the factory it deploys nothing.
+			found(
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+				"salt", ev.Salt,
+			)
+		case opt.Source == SourceScan:
+			found(
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+				"path", ev.Path,
+			)
+		case opt.Source == SourcePassphrase:
+			fields := []any{
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+				"path", ev.Path,
+			}
 			if showSecrets {
-				if opt.Source == SourceMnemonic {
-					logx.S().Infow("FOUND",
-						"kind", ev.Kind,
-						"address", ev.Address,
-						"attempt", ev.Attempt,
-						"elapsed", humanDuration(ev.Elapsed),
-						"mnemonic", ev.Mnemonic,
-						"passphrase", ev.Pass,
-						"private_key", ev.PrivateHex,
-					)
-				} else {
-					logx.S().Infow("FOUND",
-						"kind", ev.Kind,
-						"address", ev.Address,
-						"attempt", ev.Attempt,
-						"elapsed", humanDuration(ev.Elapsed),
-						"private_key", ev.PrivateHex,
-					)
-				}
+				fields = append(fields, "passphrase", ev.Pass)
+			}
+			found(fields...)
+		case opt.Source == SourceCreate:
+			fields := []any{
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"deployer", ev.Deployer,
+				"nonce", ev.Nonce,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+			}
+			if showSecrets {
+				fields = append(fields, "private_key", ev.PrivateHex)
+			}
+			found(fields...)
+		case showSecrets && opt.Source == SourceMnemonic:
+			found(
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+				"path", ev.Path,
+				"mnemonic", ev.Mnemonic,
+				"passphrase", ev.Pass,
+				"private_key", ev.PrivateHex,
+			)
+		case showSecrets:
+			keyName := "private_key"
+			if opt.Source == SourceSplitKey {
+				keyName = "partial_key"
+			}
+			fields := []any{
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+			}
+			if ev.SecretKey != "" {
+				fields = append(fields, "secret_key", ev.SecretKey)
 			} else {
-				logx.S().Infow("FOUND",
-					"kind", ev.Kind,
-					"address", ev.Address,
-					"attempt", ev.Attempt,
-					"elapsed", humanDuration(ev.Elapsed),
-				)
+				fields = append(fields, keyName, ev.PrivateHex)
 			}
+			if ev.WIF != "" {
+				fields = append(fields, "wif", ev.WIF)
+			}
+			found(fields...)
+		default:
+			found(
+				"kind", ev.Kind,
+				"address", ev.Address,
+				"attempt", ev.Attempt,
+				"elapsed", humanDuration(ev.Elapsed),
+			)
+		}
 
-			if ev.Final {
-				finalOnce.Do(func() {
-					logx.S().Infow("final reached, stop all workers")
-					cancel()
-				})
+		if ev.Match.MaxHits > 0 && hits[label] >= ev.Match.MaxHits {
+			logx.S().Infow("pattern quota reached", "pattern", label, "max_hits", ev.Match.MaxHits)
+			if matcher.Disable(ev.Match) == 0 {
+				stop("all pattern quotas reached")
+			}
+		}
+		if opt.MaxHits > 0 && totalHits >= opt.MaxHits {
+			stop("max hits")
+		}
+		if ev.Final {
+			stop("final pattern")
+		}
+	}
+
+	// Without a checkpoint ckTick stays nil and never fires.
+	var ckTick <-chan time.Time
+	if ck != nil {
+		t := time.NewTicker(checkpointEvery)
+		defer t.Stop()
+		ckTick = t.C
+	}
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				handle(ev)
+			case <-ckTick:
+				// Every hit of a key counted in the snapshot was sent
+				// before it was taken and is buffered by now: write those
+				// first so that the checkpoint never skips an unwritten hit.
+				done := ck.snapshot()
+				for n := len(events); n > 0; n-- {
+					ev, ok := <-events
+					if !ok {
+						break
+					}
+					handle(ev)
+				}
+				if err := ck.saveSnapshot(done); err != nil {
+					logx.S().Errorw("checkpoint save failed", "err", err)
+				}
 			}
 		}
 	}()
@@ -170,61 +635,212 @@ func Run(ctx context.Context, opt Options) error {
 				if elapsed > 0 {
 					rate = float64(n) / elapsed.Seconds()
 				}
-				logx.S().Infow("progress",
+				fields := []any{
 					"attempts", n,
 					"rate_addr_per_sec", fmt.Sprintf("%.2f", rate),
 					"elapsed", humanDuration(elapsed),
-				)
+					"eta", etaLines(matcher, ests, rate),
+				}
+				logx.S().Infow("progress", append(fields, mstats.fields()...)...)
 			}
 		}
 	}()
 
+	if opt.MaxAttempts > 0 {
+		go func() {
+			ticker := time.NewTicker(100 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if atomic.LoadUint64(&attempts) >= opt.MaxAttempts {
+						stop("max attempts")
+						return
+					}
+				}
+			}
+		}()
+	}
+
 	var wg sync.WaitGroup
-	wg.Add(workers)
-	switch opt.Source {
-	case SourcePrivKey:
-		for i := 0; i < workers; i++ {
-			go func() {
-				defer wg.Done()
-				workerPriv(ctx, cfg, opt.Encrypt, opt.KeystorePassword, start, &attempts, events)
-			}()
-		}
-	case SourceMnemonic:
-		for i := 0; i < workers; i++ {
-			go func() {
-				defer wg.Done()
-				workerMnemonic(ctx, cfg, opt.WordsStrength, opt.Passphrase, opt.DeriveN, start, &attempts, events)
-			}()
-		}
-	default:
+	src := &sourceState{ck: ck, splitPub: splitPub, c2: c2, mstats: mstats, sampler: sampler, scan: scan, pass: pg}
+	if err := spawnWorkers(ctx, opt, matcher, src, start, &attempts, events, &wg); err != nil {
 		cancel()
-		wg.Done()
-		return fmt.Errorf("unknown source: %s", opt.Source)
+		close(events)
+		<-writerDone
+		<-statusDone
+		return err
 	}
 
+	// Workers also return on their own when a finite source (a scan range)
+	// is exhausted; release the status goroutine then too.
 	wg.Wait()
+	if scan != nil {
+		// The scan reports its lowest hit once the chunks below it are done.
+		if ev, ok := scan.result(); ok {
+			if ctx.Err() != nil {
+				logx.S().Warnw("scan stopped before all positions below the hit were tested; a lower path may match too",
+					"path", ev.Path)
+			}
+			events <- ev
+		}
+	}
+	cancel()
 	close(events)
 	<-writerDone
 	<-statusDone
 
-	logx.S().Infow("stopped",
+	if ck != nil {
+		done := ck.snapshot()
+		if err := ck.saveSnapshot(done); err != nil {
+			logx.S().Errorw("checkpoint save failed", "err", err)
+		} else {
+			logx.S().Infow("checkpoint saved", "path", ck.path, "tested", ck.tested(done))
+		}
+	}
+
+	// Settle the reason under stopOnce so that a late timer cannot race it.
+	stopOnce.Do(func() {
+		stopReason = "completed"
+		if parent.Err() != nil {
+			stopReason = "interrupted"
+		}
+	})
+	fields := []any{
+		"reason", stopReason,
 		"elapsed", humanDuration(time.Since(start)),
 		"attempts", atomic.LoadUint64(&attempts),
-	)
-	return ctx.Err()
+		"hits", totalHits,
+		"hits_by_pattern", hits,
+	}
+	logx.S().Infow("stopped", append(fields, mstats.fields()...)...)
+	return parent.Err()
+}
+
+// sourceState is what the workers of some sources share within a run.
+type sourceState struct {
+	ck       *checkpoint       // seeded search: worker i walks range i
+	splitPub *ecdsa.PublicKey  // SourceSplitKey
+	c2       *create2Target    // SourceCreate2
+	mstats   *mnemonicStats    // SourceMnemonic and SourcePassphrase, optional
+	sampler  *mnemonic.Sampler // SourceMnemonic with a mnemonic section
+	scan     *scanRange        // SourceScan
+	pass     *passGen          // SourcePassphrase
+}
+
+// spawnWorkers starts opt.Workers workers of opt.Source. wg is released as
+// each of them exits.
+func spawnWorkers(
+	ctx context.Context,
+	opt Options,
+	m *patterns.Matcher,
+	src *sourceState,
+	start time.Time,
+	attempts *uint64,
+	out chan<- foundEvent,
+	wg *sync.WaitGroup,
+) error {
+	var work func(i int)
+	switch opt.Source {
+	case SourcePrivKey, SourceCreate:
+		if opt.Format == config.FormatSolana {
+			work = func(int) {
+				workerSolana(ctx, m, start, attempts, out)
+			}
+			break
+		}
+		if opt.Format.Script() == "p2tr" {
+			work = func(int) {
+				workerTaproot(ctx, m, start, attempts, out)
+			}
+			break
+		}
+		work = func(i int) {
+			cr := newCreator(opt)
+			if ck := src.ck; ck != nil {
+				w, err := crypto.NewWalkerAt(ck.rangeStart(i), ck.done[i].Load(), crypto.DefaultWalkBatch)
+				if err != nil {
+					logx.S().Errorw("walker init failed", "range", i, "err", err)
+					return
+				}
+				if opt.Format.KeyHash() {
+					w.UseKeyHash()
+				}
+				workerPrivWalk(ctx, m, cr, opt.Encrypt, opt.KeystorePassword, w, &ck.done[i], start, attempts, out)
+				return
+			}
+			if opt.Incremental {
+				w, err := crypto.NewRandomWalker(crypto.DefaultWalkBatch)
+				if err != nil {
+					logx.S().Errorw("walker init failed", "err", err)
+					return
+				}
+				if opt.Format.KeyHash() {
+					w.UseKeyHash()
+				}
+				workerPrivWalk(ctx, m, cr, opt.Encrypt, opt.KeystorePassword, w, nil, start, attempts, out)
+				return
+			}
+			workerPriv(ctx, m, cr, opt.Encrypt, opt.KeystorePassword, start, attempts, out)
+		}
+	case SourceSplitKey:
+		work = func(int) {
+			w, err := crypto.NewSplitWalker(src.splitPub, crypto.DefaultWalkBatch)
+			if err != nil {
+				logx.S().Errorw("walker init failed", "err", err)
+				return
+			}
+			workerPrivWalk(ctx, m, nil, false, "", w, nil, start, attempts, out)
+		}
+	case SourceCreate2:
+		work = func(int) {
+			workerCreate2(ctx, m, src.c2, start, attempts, out)
+		}
+	case SourceMnemonic:
+		wl, err := mnemonic.NewWordlist(opt.WordsLang)
+		if err != nil {
+			return err
+		}
+		work = func(int) {
+			workerMnemonic(ctx, m, wl, src.sampler, opt.WordsStrength, opt.Passphrase, opt.DerivePaths, opt.DeriveN, src.mstats, start, attempts, out)
+		}
+	case SourcePassphrase:
+		work = func(int) {
+			workerPassphrase(ctx, m, src.pass, opt.DerivePaths, opt.DeriveN, src.mstats, start, attempts, out)
+		}
+	case SourceScan:
+		work = func(int) {
+			workerScan(ctx, m, src.scan, start, attempts)
+		}
+	default:
+		return fmt.Errorf("unknown source: %s", opt.Source)
+	}
+
+	wg.Add(opt.Workers)
+	for i := 0; i < opt.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			work(i)
+		}()
+	}
+	return nil
 }
 
 // =============================== WORKERS ===============================
 
 func workerPriv(
 	ctx context.Context,
-	cfg *config.PatternsConfig,
+	m *patterns.Matcher,
+	cr *creator,
 	encrypt bool,
 	ksPwd string,
 	start time.Time,
 	attempts *uint64,
 	out chan<- foundEvent,
 ) {
+	net := wifNet(m.Format())
 	for {
 		select {
 		case <-ctx.Done():
@@ -233,24 +849,33 @@ func workerPriv(
 		}
 
 		priv, err := crypto.NewPrivKey()
-		n := atomic.AddUint64(attempts, 1)
+		n := atomic.AddUint64(attempts, cr.perKey())
 		if err != nil {
 			logx.S().Errorw("generate priv failed", "err", err)
 			continue
 		}
-		addr := crypto.AddressHex(priv)
-
-		mr := patterns.MatchAddress(cfg, addr)
+		raw := crypto.Address(priv)
+		if m.Format().KeyHash() {
+			raw = crypto.KeyHash(priv)
+		}
+		mr, hit, nonce := cr.match(m, &raw)
 		if mr == nil {
 			continue
 		}
+		addr, evm := addresses(m.Format(), hit[:])
 
 		ev := foundEvent{
-			Kind:    mr.Kind,
-			Address: addr,
-			Elapsed: time.Since(start),
-			Attempt: n,
-			Final:   mr.Final,
+			Kind:       mr.Kind,
+			Address:    addr,
+			EVMAddress: evm,
+			Elapsed:    time.Since(start),
+			Attempt:    n,
+			Final:      mr.Final,
+			Match:      mr,
+		}
+		if cr != nil {
+			ev.Deployer = common.Address(raw).Hex()
+			ev.Nonce = nonce
 		}
 
 		if encrypt {
@@ -263,6 +888,9 @@ func workerPriv(
 		} else {
 			ev.PrivateHex = crypto.PrivToHex(priv)
 		}
+		if net != nil {
+			ev.WIF = crypto.WIF(priv, net)
+		}
 
 		select {
 		case <-ctx.Done():
@@ -272,16 +900,127 @@ func workerPriv(
 	}
 }
 
+// workerPrivWalk walks k, k+1, k+2… by point addition from the walker's
+// base (or from the origin of a split walk, emitting partial keys). A hit is
+// re-derived from base+offset and verified against the address before it is
+// emitted. With done set, the offset up to which every
+// key has been tested and its hit handed over is published there.
+func workerPrivWalk(
+	ctx context.Context,
+	m *patterns.Matcher,
+	cr *creator,
+	encrypt bool,
+	ksPwd string,
+	w *crypto.Walker,
+	done *atomic.Uint64,
+	start time.Time,
+	attempts *uint64,
+	out chan<- foundEvent,
+) {
+	net := wifNet(m.Format())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		first, addrs := w.Next()
+		per := cr.perKey()
+		n := atomic.AddUint64(attempts, uint64(len(addrs))*per) - uint64(len(addrs))*per
+
+		for i := range addrs {
+			raw := &addrs[i]
+			mr, hit, nonce := cr.match(m, raw)
+			if mr == nil {
+				continue
+			}
+			addr, evm := addresses(m.Format(), hit[:])
+
+			ev := foundEvent{
+				Kind:       mr.Kind,
+				Address:    addr,
+				EVMAddress: evm,
+				Elapsed:    time.Since(start),
+				Attempt:    n + (uint64(i)+1)*per,
+				Final:      mr.Final,
+				Match:      mr,
+			}
+			if cr != nil {
+				ev.Deployer = common.Address(*raw).Hex()
+				ev.Nonce = nonce
+			}
+
+			if w.Split() {
+				partial, err := w.PartialKey(first+uint64(i), *raw)
+				if err != nil {
+					logx.S().Errorw("walker key verification failed", "addr", addr, "err", err)
+					continue
+				}
+				ev.PrivateHex = "0x" + hex.EncodeToString(partial[:])
+				clear(partial[:])
+			} else if priv, err := w.PrivKey(first+uint64(i), *raw); err != nil {
+				logx.S().Errorw("walker key verification failed", "addr", addr, "err", err)
+				continue
+			} else if encrypt {
+				blob, err := crypto.KeystoreJSON(priv, ksPwd)
+				if err != nil {
+					logx.S().Errorw("keystore encrypt failed", "addr", addr, "err", err)
+					continue
+				}
+				ev.KsJSON = blob
+			} else {
+				ev.PrivateHex = crypto.PrivToHex(priv)
+				if net != nil {
+					ev.WIF = crypto.WIF(priv, net)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				// This key's hit was not handed over: test it again on
+				// resume.
+				if done != nil {
+					done.Store(first + uint64(i))
+				}
+				return
+			case out <- ev:
+			}
+		}
+		if done != nil {
+			done.Store(first + uint64(len(addrs)))
+		}
+	}
+}
+
+// workerMnemonic draws phrases from wl, or from sampler when the words are
+// constrained, and matches the addresses derived from each.
 func workerMnemonic(
 	ctx context.Context,
-	cfg *config.PatternsConfig,
+	m *patterns.Matcher,
+	wl *mnemonic.Wordlist,
+	sampler *mnemonic.Sampler,
 	strength int,
 	pass string,
+	paths []string,
 	deriveN int,
+	stats *mnemonicStats,
 	start time.Time,
 	attempts *uint64,
 	out chan<- foundEvent,
 ) {
+	d, err := mnemonic.NewDeriver(paths)
+	if err != nil {
+		logx.S().Errorw("mnemonic deriver init failed", "err", err)
+		return
+	}
+	if m.Format().KeyHash() {
+		d.UseKeyHash()
+	}
+	if deriveN <= 0 {
+		deriveN = 5
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -289,42 +1028,81 @@ func workerMnemonic(
 		default:
 		}
 
-		mn, err := mnemonic.NewMnemonic(strength)
+		var mn string
+		var entropy []byte
+		if sampler != nil {
+			mn, entropy, err = sampler.New()
+		} else {
+			mn, entropy, err = wl.New(strength)
+		}
 		if err != nil {
 			logx.S().Errorw("mnemonic generate failed", "err", err)
 			continue
 		}
-		derived, err := mnemonic.Derive(mn, pass, deriveN)
+		t0 := time.Now()
+		seed := mnemonic.Seed(mn, pass)
+		t1 := time.Now()
+		children, err := d.Derive(seed, deriveN)
+		stats.add(t1.Sub(t0), time.Since(t1), len(children))
 		if err != nil {
 			logx.S().Errorw("mnemonic derive failed", "err", err)
 			continue
 		}
 
-		for _, d := range derived {
+		// A standalone mnemonic section makes the phrase itself the hit;
+		// its first address stands for it.
+		phrase := m.MatchPhrase()
+		if phrase != nil {
+			children = children[:1]
+		}
+
+		for i := range children {
 			select {
 			case <-ctx.Done():
 				return
 			default:
 			}
 
+			c := &children[i]
 			n := atomic.AddUint64(attempts, 1)
-			addr := d.Address
-			mr := patterns.MatchAddress(cfg, addr)
+			mr := phrase
+			if mr == nil {
+				mr = m.MatchRaw(&c.Address)
+			}
 			if mr == nil {
 				continue
 			}
+			addr, evm := addresses(m.Format(), c.Address[:])
+			if err := wl.Verify(mn, entropy); err != nil {
+				logx.S().Errorw("mnemonic round-trip failed", "addr", addr, "wordlist", wl.Lang, "err", err)
+				continue
+			}
+			if sampler != nil {
+				if err := sampler.Check(entropy); err != nil {
+					logx.S().Errorw("mnemonic constraints check failed", "addr", addr, "err", err)
+					continue
+				}
+			}
+			priv, err := c.PrivKey()
+			if err != nil {
+				logx.S().Errorw("mnemonic key failed", "addr", addr, "err", err)
+				continue
+			}
 
 			ev := foundEvent{
 				Kind:       mr.Kind,
 				Address:    addr,
-				PrivateHex: crypto.PrivToHex(d.Priv),
-				Mnemonic:   d.Mnemonic,
+				EVMAddress: evm,
+				PrivateHex: crypto.PrivToHex(priv),
+				Mnemonic:   mn,
 				Pass:       pass,
-				Path:       d.Path,
-				Index:      d.Index,
+				Template:   c.Template,
+				Path:       c.Path(),
+				Index:      c.Index,
 				Elapsed:    time.Since(start),
 				Attempt:    n,
 				Final:      mr.Final,
+				Match:      mr,
 			}
 
 			select {
@@ -342,6 +1120,16 @@ func workerMnemonic(
 
 // ------------------------------- helpers ------------------------------------
 
+// addresses renders payload raw in format f, with the EVM address of the
+// same key for Tron, which shares it.
+func addresses(f config.AddressFormat, raw []byte) (addr, evm string) {
+	addr = patterns.Render(f, raw)
+	if f == config.FormatTron {
+		evm = common.BytesToAddress(raw).Hex()
+	}
+	return addr, evm
+}
+
 func humanDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
@@ -357,6 +1145,68 @@ func humanDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%02dm%02ds", h, m, s)
 }
 
+// etaLines renders the expected, 50% and 90% times to a hit of every pattern
+// at the given rate. Hits are memoryless, so these do not count down with the
+// elapsed time.
+func etaLines(m *patterns.Matcher, ests []patterns.Estimate, rate float64) []string {
+	out := make([]string, 0, len(ests))
+	for _, e := range ests {
+		if !m.Enabled(e) {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s p=%s exp=%s p50=%s p90=%s",
+			e.Label(), probString(e),
+			atLeast(e, humanETA(e.Expected(), rate)),
+			atLeast(e, humanETA(e.Attempts(0.5), rate)),
+			atLeast(e, humanETA(e.Attempts(0.9), rate)),
+		))
+	}
+	return out
+}
+
+func probString(e patterns.Estimate) string {
+	switch {
+	case e.Bound && e.Empirical:
+		return fmt.Sprintf("<1/%d (sampled)", e.Samples)
+	case e.Bound:
+		return fmt.Sprintf("<1/%.4g", e.Expected())
+	case e.Empirical:
+		return fmt.Sprintf("~1/%.4g (sampled)", e.Expected())
+	case e.P == 0:
+		return "0"
+	}
+	return fmt.Sprintf("1/%.4g", e.Expected())
+}
+
+// atLeast marks an attempt count or ETA of e as a lower bound when e's
+// probability is an upper one.
+func atLeast(e patterns.Estimate, s string) string {
+	switch {
+	case !e.Bound || s == "?":
+		return s
+	case s == "<1s":
+		return ">0s"
+	}
+	return ">" + s
+}
+
+// humanETA converts a number of attempts into a duration at rate attempts/s.
+func humanETA(attempts, rate float64) string {
+	if rate <= 0 {
+		return "?"
+	}
+	sec := attempts / rate
+	switch {
+	case math.IsInf(sec, 1):
+		return "never"
+	case sec < 1:
+		return "<1s"
+	case sec >= 365*24*3600:
+		return fmt.Sprintf("%.3gy", sec/(365*24*3600))
+	}
+	return humanDuration(time.Duration(sec * float64(time.Second)))
+}
+
 func appendJSONL(dir, kind string, blob []byte) error {
 	path := filepath.Join(dir, kind+".jsonl")
 	return keystore.AppendJSONL(path, blob)
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// mnemonicStats splits the time of the mnemonic workers between the PBKDF2
+// seed stretch, paid once per phrase, and the BIP-32 derivation, paid per
+// address. A nil *mnemonicStats ignores updates and reports nothing.
+type mnemonicStats struct {
+	seeds, derived         atomic.Uint64
+	seedNanos, deriveNanos atomic.Int64
+}
+
+func (s *mnemonicStats) add(seed, derive time.Duration, addrs int) {
+	if s == nil {
+		return
+	}
+	s.seeds.Add(1)
+	s.derived.Add(uint64(addrs))
+	s.seedNanos.Add(int64(seed))
+	s.deriveNanos.Add(int64(derive))
+}
+
+// fields are log fields with the throughput of each phase per second spent
+// in it, summed over workers, and the share of time spent stretching seeds.
+func (s *mnemonicStats) fields() []any {
+	if s == nil {
+		return nil
+	}
+	seedSec := time.Duration(s.seedNanos.Load()).Seconds()
+	deriveSec := time.Duration(s.deriveNanos.Load()).Seconds()
+	if seedSec == 0 || deriveSec == 0 {
+		return nil
+	}
+	return []any{
+		"seeds_per_sec", fmt.Sprintf("%.2f", float64(s.seeds.Load())/seedSec),
+		"derive_addr_per_sec", fmt.Sprintf("%.2f", float64(s.derived.Load())/deriveSec),
+		"seed_time_share", fmt.Sprintf("%.0f%%", 100*seedSec/(seedSec+deriveSec)),
+	}
+}
@@ -1,25 +1,98 @@
 package generator
 
+import (
+	"time"
+
+	"WalletTools/pkg/config"
+)
+
 type Source string
 
 const (
-	SourcePrivKey  Source = "private"
-	SourceMnemonic Source = "mnemonics"
+	SourcePrivKey    Source = "private"
+	SourceMnemonic   Source = "mnemonics"
+	SourceSplitKey   Source = "split"
+	SourceCreate2    Source = "create2"
+	SourceCreate     Source = "create"
+	SourceScan       Source = "scan"
+	SourcePassphrase Source = "passphrase"
 )
 
 type Options struct {
 	Source           Source
 	Encrypt          bool
 	KeystorePassword string
+	Incremental      bool // walk k, k+1, k+2… from a random base instead of a fresh key per attempt
+
+	// Seeded walks the keyspace deterministically from a random secret seed,
+	// one range per worker, recording progress in checkpoint.json of the run
+	// directory. The seed is stored there encrypted with SeedPassword.
+	// ResumeDir continues the interrupted run in that directory; keys tested
+	// before are not tested again, so a run whose patterns file changed since
+	// is only resumed with ResumeChangedPatterns.
+	Seeded                bool
+	SeedPassword          string
+	ResumeDir             string
+	ResumeChangedPatterns bool
+
+	// SplitPubKey is the owner's public key for SourceSplitKey: only partial
+	// keys are found, the final key is assembled with crypto.CombineSplitKey.
+	SplitPubKey string
+
+	// CREATE2 search: salts are tried for a fixed factory (deployer) and
+	// keccak256 of the contract init code, both hex.
+	Create2Deployer     string
+	Create2InitCodeHash string
 
-	WordsStrength int    // for mnemonic, 128=12 words
-	DeriveN       int    // number of accounts to derive per mnemonic
-	Passphrase    string // BIP-39 passphrase (not encryption!)
+	// SourceCreate matches the contract addresses a deployer key creates with
+	// the nonces 0..CreateMaxNonce; keys come from the private key stream.
+	CreateMaxNonce uint64
+
+	// FixedMnemonic is the existing wallet of SourceScan and SourcePassphrase.
+	FixedMnemonic string
+
+	// SourceScan searches the addresses of FixedMnemonic (with Passphrase):
+	// the seed is computed once and the accounts 0..ScanAccounts-1 × indexes
+	// 0..ScanIndexes-1 of ScanPath are tested in parallel; the first matching
+	// path, accounts outermost, is the one hit reported. Zero ScanIndexes
+	// means all 2^31, for a single account only.
+	ScanPath     string // template with {i} and optionally {a}; empty = m/44'/60'/{a}'/0/{i}
+	ScanAccounts int
+	ScanIndexes  int
+
+	// SourcePassphrase keeps FixedMnemonic and draws random passphrases,
+	// deriving DeriveN addresses along DerivePaths for each: PassWords words
+	// of the mnemonic's wordlist when set, else PassLength characters of
+	// PassCharset (default letters and digits, 12 characters).
+	PassCharset string
+	PassLength  int
+	PassWords   int
+
+	WordsStrength int      // for mnemonic, 128=12 words … 256=24 words
+	WordsLang     string   // BIP-39 wordlist, see mnemonic.Languages; empty = english
+	DeriveN       int      // number of accounts to derive per mnemonic
+	DerivePaths   []string // path templates with {i} or aliases bip44|ledger|mew|tron|cosmos; empty = bip44, cosmos for Cosmos formats
+	Passphrase    string   // BIP-39 passphrase (not encryption!)
 
 	LogsBase      string // logs
 	PassHint      string // hint.txt
 	PatternsPath  string // configs/patterns.yaml
 	CaseMaskedOut bool   // console masking (handled by logx/masking_core)
 
+	// Format is the address form the patterns are matched against; Tron
+	// results keep the EVM address as evm_address. Bitcoin formats are only
+	// searched with SourcePrivKey, unencrypted, and their results carry the
+	// key as WIF; so is FormatSolana, whose ed25519 results carry the
+	// keypair instead of a private key. Cosmos formats are searched with
+	// SourcePrivKey, unencrypted, and SourceMnemonic. Empty means
+	// config.FormatEVM.
+	Format config.AddressFormat
+
 	Workers int
+
+	// Run limits; zero means unlimited. The run ends cleanly with a summary
+	// when any of them is reached.
+	MaxAttempts uint64
+	MaxDuration time.Duration
+	MaxHits     int // results written in total
 }
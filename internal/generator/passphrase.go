@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"WalletTools/internal/mnemonic"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/logx"
+)
+
+// DefaultPassCharset is the passphrase alphabet when none is given.
+const DefaultPassCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// logPassphrase is a hit of a passphrase search: the mnemonic is the
+// operator's own backup, the passphrase and path select the wallet.
+type logPassphrase struct {
+	Address    string   `json:"address"`
+	EVMAddress string   `json:"evm_address,omitempty"`
+	Passphrase string   `json:"passphrase"`
+	Path       string   `json:"path"`
+	Word       *logWord `json:"word,omitempty"`
+}
+
+// passGen draws uniformly random passphrases: length symbols of alphabet,
+// joined by sep (a space for diceware words, nothing for characters).
+type passGen struct {
+	mnemonic string
+	lang     string
+	alphabet []string
+	length   int
+	sep      string
+}
+
+func newPassGen(opt Options) (*passGen, error) {
+	wl, err := mnemonic.DetectWordlist(opt.FixedMnemonic, opt.WordsLang)
+	if err != nil {
+		return nil, fmt.Errorf("passphrase search mnemonic: %w", err)
+	}
+	g := &passGen{mnemonic: opt.FixedMnemonic, lang: wl.Lang}
+	if opt.PassWords > 0 {
+		g.alphabet, g.length, g.sep = wl.Words(), opt.PassWords, " "
+		return g, nil
+	}
+
+	charset := opt.PassCharset
+	if charset == "" {
+		charset = DefaultPassCharset
+	}
+	seen := make(map[rune]bool)
+	for _, r := range charset {
+		if !seen[r] {
+			seen[r] = true
+			g.alphabet = append(g.alphabet, string(r))
+		}
+	}
+	if len(g.alphabet) < 2 {
+		return nil, errors.New("passphrase charset needs at least two distinct characters")
+	}
+	g.length = opt.PassLength
+	if g.length == 0 {
+		g.length = 12
+	}
+	if g.length < 0 {
+		return nil, fmt.Errorf("invalid passphrase length %d", g.length)
+	}
+	return g, nil
+}
+
+// bits is the entropy of one passphrase.
+func (g *passGen) bits() float64 {
+	return float64(g.length) * math.Log2(float64(len(g.alphabet)))
+}
+
+func (g *passGen) next() (string, error) {
+	n := big.NewInt(int64(len(g.alphabet)))
+	parts := make([]string, g.length)
+	for i := range parts {
+		v, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = g.alphabet[v.Int64()]
+	}
+	return strings.Join(parts, g.sep), nil
+}
+
+// workerPassphrase pays one seed stretch per passphrase, then derives
+// deriveN addresses along every template from it.
+func workerPassphrase(
+	ctx context.Context,
+	m *patterns.Matcher,
+	g *passGen,
+	paths []string,
+	deriveN int,
+	stats *mnemonicStats,
+	start time.Time,
+	attempts *uint64,
+	out chan<- foundEvent,
+) {
+	d, err := mnemonic.NewDeriver(paths)
+	if err != nil {
+		logx.S().Errorw("mnemonic deriver init failed", "err", err)
+		return
+	}
+	if deriveN <= 0 {
+		deriveN = 5
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		pass, err := g.next()
+		if err != nil {
+			logx.S().Errorw("passphrase generate failed", "err", err)
+			continue
+		}
+		t0 := time.Now()
+		seed := mnemonic.Seed(g.mnemonic, pass)
+		t1 := time.Now()
+		children, err := d.Derive(seed, deriveN)
+		stats.add(t1.Sub(t0), time.Since(t1), len(children))
+		if err != nil {
+			logx.S().Errorw("mnemonic derive failed", "err", err)
+			continue
+		}
+
+		for i := range children {
+			c := &children[i]
+			n := atomic.AddUint64(attempts, 1)
+			mr := m.MatchRaw(&c.Address)
+			if mr == nil {
+				continue
+			}
+
+			addr, evm := addresses(m.Format(), c.Address[:])
+			ev := foundEvent{
+				Kind:       mr.Kind,
+				Address:    addr,
+				EVMAddress: evm,
+				Pass:       pass,
+				Template:   c.Template,
+				Path:       c.Path(),
+				Index:      c.Index,
+				Elapsed:    time.Since(start),
+				Attempt:    n,
+				Final:      mr.Final,
+				Match:      mr,
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- ev:
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"WalletTools/internal/mnemonic"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/logx"
+)
+
+// scanChunk is how many positions a scan worker claims at a time.
+const scanChunk = 4096
+
+// logScan is a hit of a fixed-mnemonic scan. The wallet is the user's own, so
+// only the path is recorded: the mnemonic opens it.
+type logScan struct {
+	Address    string   `json:"address"`
+	EVMAddress string   `json:"evm_address,omitempty"`
+	Path       string   `json:"path"`
+	Account    int      `json:"account"`
+	Index      int      `json:"index"`
+	Word       *logWord `json:"word,omitempty"`
+}
+
+// scanRange is the accounts × indexes space of a fixed-mnemonic scan. Workers
+// claim chunks of it in order, accounts outermost, and the range ends the run
+// once it is exhausted. Chunks run in parallel, so a hit only bounds the
+// range: positions above it are dropped while the chunks below it finish,
+// and the lowest hit is the first matching path.
+type scanRange struct {
+	seed     []byte
+	lang     string
+	first    string // address at account 0, index 0
+	template string
+	accounts uint64
+	indexes  uint64
+	next     atomic.Uint64 // next chunk to claim
+	bound    atomic.Uint64 // positions from here on are not tested
+
+	mu  sync.Mutex
+	hit *foundEvent // at position bound
+}
+
+func newScanRange(opt Options) (*scanRange, error) {
+	wl, err := mnemonic.DetectWordlist(opt.FixedMnemonic, opt.WordsLang)
+	if err != nil {
+		return nil, fmt.Errorf("scan mnemonic: %w", err)
+	}
+	r := &scanRange{
+		seed:     mnemonic.Seed(opt.FixedMnemonic, opt.Passphrase),
+		lang:     wl.Lang,
+		template: opt.ScanPath,
+		accounts: uint64(max(opt.ScanAccounts, 1)),
+		indexes:  uint64(opt.ScanIndexes),
+	}
+	if r.indexes == 0 {
+		r.indexes = mnemonic.MaxScanIndex
+	}
+	r.bound.Store(r.accounts * r.indexes)
+	// Check the template and the wallet up front rather than in every worker.
+	s, err := mnemonic.NewScanner(r.seed, r.template)
+	if err != nil {
+		return nil, err
+	}
+	r.template = s.Template()
+	switch {
+	case r.accounts > 1 && !s.HasAccount():
+		return nil, fmt.Errorf("scan template %q has no %s to scan %d accounts", r.template, mnemonic.AccountPlaceholder, r.accounts)
+	case r.accounts > 1 && opt.ScanIndexes == 0:
+		// Accounts are outermost: account 1 would wait for all 2^31
+		// indexes of account 0.
+		return nil, fmt.Errorf("scanning %d accounts needs a bound on the indexes per account", r.accounts)
+	case r.accounts > mnemonic.MaxScanIndex || r.indexes > mnemonic.MaxScanIndex:
+		return nil, errors.New("scan range exceeds the non-hardened index space")
+	}
+	// The first address identifies the wallet without revealing it: a
+	// mistyped phrase or passphrase shows up as an unfamiliar address.
+	c, err := s.Derive(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	r.first, _ = addresses(opt.Format, c.Address[:])
+	return r, nil
+}
+
+func (r *scanRange) total() uint64 { return r.accounts * r.indexes }
+
+// record keeps ev, the hit at pos, if it is the lowest so far, and bounds the
+// range to it.
+func (r *scanRange) record(pos uint64, ev foundEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pos >= r.bound.Load() {
+		return
+	}
+	r.hit = &ev
+	r.bound.Store(pos)
+}
+
+// result is the lowest hit, if any.
+func (r *scanRange) result() (foundEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hit == nil {
+		return foundEvent{}, false
+	}
+	return *r.hit, true
+}
+
+func workerScan(
+	ctx context.Context,
+	m *patterns.Matcher,
+	r *scanRange,
+	start time.Time,
+	attempts *uint64,
+) {
+	s, err := mnemonic.NewScanner(r.seed, r.template)
+	if err != nil {
+		logx.S().Errorw("scanner init failed", "err", err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// Chunks are claimed in order: once one starts above the bound,
+		// so do all the later ones.
+		bound := r.bound.Load()
+		from := (r.next.Add(1) - 1) * scanChunk
+		if from >= bound {
+			return
+		}
+		to := min(from+scanChunk, bound)
+		n := atomic.AddUint64(attempts, to-from) - (to - from)
+
+		for pos := from; pos < to && pos < r.bound.Load(); pos++ {
+			c, err := s.Derive(int(pos/r.indexes), int(pos%r.indexes))
+			if err != nil {
+				logx.S().Errorw("scan derive failed", "account", pos/r.indexes, "index", pos%r.indexes, "err", err)
+				continue
+			}
+			mr := m.MatchRaw(&c.Address)
+			if mr == nil {
+				continue
+			}
+
+			addr, evm := addresses(m.Format(), c.Address[:])
+			ev := foundEvent{
+				Kind:       mr.Kind,
+				Address:    addr,
+				EVMAddress: evm,
+				Template:   c.Template,
+				Path:       c.Path(),
+				Account:    c.Account,
+				Index:      c.Index,
+				Elapsed:    time.Since(start),
+				Attempt:    n + pos - from + 1,
+				Final:      mr.Final,
+				Match:      mr,
+			}
+			// The rest of the chunk is above this hit.
+			r.record(pos, ev)
+			break
+		}
+	}
+}
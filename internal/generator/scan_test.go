@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"WalletTools/internal/mnemonic"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/config"
+)
+
+const abandonAbout = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// scanMatcher matches exactly the addresses at the given indexes of account 0.
+func scanMatcher(t *testing.T, indexes ...int) *patterns.Matcher {
+	t.Helper()
+	s, err := mnemonic.NewScanner(mnemonic.Seed(abandonAbout, ""), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bodies []string
+	for _, i := range indexes {
+		c, err := s.Derive(0, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies = append(bodies, hex.EncodeToString(c.Address[:]))
+	}
+	m, err := patterns.New(&config.PatternsConfig{
+		Format: config.FormatEVM,
+		Regexp: []config.RegexpPattern{{Pattern: "^(" + strings.Join(bodies, "|") + ")$"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// Chunks run in parallel, and a hit early in a later chunk usually comes
+// before one late in an earlier chunk: the earlier one is still reported.
+func TestScanReportsLowestHit(t *testing.T) {
+	tests := []struct {
+		hits []int
+		want int
+	}{
+		{[]int{scanChunk - 10, scanChunk + 10, 2*scanChunk + 10}, scanChunk - 10},
+		{[]int{scanChunk + 10, 2*scanChunk + 10}, scanChunk + 10},
+		{[]int{3, 5}, 3},
+	}
+	for _, tt := range tests {
+		m := scanMatcher(t, tt.hits...)
+		r, err := newScanRange(Options{FixedMnemonic: abandonAbout, ScanIndexes: 3 * scanChunk, Format: config.FormatEVM})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var attempts uint64
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				workerScan(context.Background(), m, r, time.Now(), &attempts)
+			}()
+		}
+		wg.Wait()
+
+		ev, ok := r.result()
+		if !ok {
+			t.Fatalf("hits %v: nothing found", tt.hits)
+		}
+		if ev.Account != 0 || ev.Index != tt.want {
+			t.Errorf("hits %v: got %s, want index %d", tt.hits, ev.Path, tt.want)
+		}
+	}
+}
+
+func TestScanNoHit(t *testing.T) {
+	m := scanMatcher(t, 500)
+	r, err := newScanRange(Options{FixedMnemonic: abandonAbout, ScanIndexes: 100, Format: config.FormatEVM})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attempts uint64
+	workerScan(context.Background(), m, r, time.Now(), &attempts)
+	if ev, ok := r.result(); ok {
+		t.Errorf("got %s beyond the range", ev.Path)
+	}
+	if attempts != 100 {
+		t.Errorf("got %d attempts, want 100", attempts)
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"WalletTools/internal/crypto"
+	"WalletTools/internal/patterns"
+	"WalletTools/pkg/logx"
+)
+
+// solanaSeedBatch is the number of 32-byte seeds read from the system
+// random source at once.
+const solanaSeedBatch = 64
+
+// workerSolana draws ed25519 keys from fresh random seeds. Unlike secp256k1
+// there is no cheap walk: the scalar is hashed from the seed, so every key
+// costs a full base point multiplication.
+func workerSolana(
+	ctx context.Context,
+	m *patterns.Matcher,
+	start time.Time,
+	attempts *uint64,
+	out chan<- foundEvent,
+) {
+	var seeds [solanaSeedBatch * ed25519.SeedSize]byte
+	defer clear(seeds[:])
+	for {
+		if _, err := rand.Read(seeds[:]); err != nil {
+			logx.S().Errorw("generate seed failed", "err", err)
+			return
+		}
+		for off := 0; off < len(seeds); off += ed25519.SeedSize {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			n := atomic.AddUint64(attempts, 1)
+			priv := crypto.NewSolanaKey(seeds[off : off+ed25519.SeedSize])
+			mr := m.MatchPayload(priv[ed25519.SeedSize:])
+			if mr == nil {
+				continue
+			}
+			ev, err := solanaHit(priv)
+			clear(priv)
+			if err != nil {
+				logx.S().Errorw("solana key verification failed", "err", err)
+				continue
+			}
+			ev.Kind, ev.Match, ev.Final = mr.Kind, mr, mr.Final
+			ev.Elapsed, ev.Attempt = time.Since(start), n
+			select {
+			case <-ctx.Done():
+				return
+			case out <- ev:
+			}
+		}
+	}
+}
+
+// solanaHit re-derives the key from its seed and checks a signature against
+// the matched public key before the secret is handed out.
+func solanaHit(priv ed25519.PrivateKey) (foundEvent, error) {
+	again := crypto.NewSolanaKey(priv.Seed())
+	defer clear(again)
+	pub := priv.Public().(ed25519.PublicKey)
+	if !bytes.Equal(again.Public().(ed25519.PublicKey), pub) {
+		return foundEvent{}, errors.New("public key mismatch")
+	}
+	msg := []byte("WalletTools")
+	if !ed25519.Verify(pub, msg, ed25519.Sign(again, msg)) {
+		return foundEvent{}, errors.New("signature check failed")
+	}
+	return foundEvent{
+		Address:   crypto.SolanaAddress(again),
+		SecretKey: crypto.SolanaSecret(again),
+		Keypair:   crypto.SolanaKeypairJSON(again),
+	}, nil
+}
@@ -2,23 +2,20 @@ package logsink
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
 
+// WriteMatch appends a hit to the file of its kind in dir: a pattern kind
+// or the output of a rule, kind.json with asJSON and kind.log otherwise.
 func WriteMatch(dir, kind string, payload interface{}, asJSON bool) error {
-	var fname string
-	switch kind {
-	case "symmetric", "specific", "edges", "regexp":
-		if asJSON {
-			fname = kind + ".json"
-		} else {
-			fname = kind + ".log"
-		}
-	case "app":
-		fname = "app.log"
-	default:
-		fname = kind + ".log"
+	if kind == "" || kind != filepath.Base(kind) || kind == ".." {
+		return fmt.Errorf("invalid match kind %q", kind)
+	}
+	fname := kind + ".log"
+	if asJSON && kind != "app" {
+		fname = kind + ".json"
 	}
 	path := filepath.Join(dir, fname)
 	f, err := OpenAppend(path)
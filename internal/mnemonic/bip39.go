@@ -3,14 +3,73 @@ package mnemonic
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"strconv"
+	"strings"
 
-	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
+	"github.com/ethereum/go-ethereum/common"
 	bip39 "github.com/tyler-smith/go-bip39"
 )
 
+// IndexPlaceholder marks the position of the address index in a derivation
+// path template.
+const IndexPlaceholder = "{i}"
+
+// AccountPlaceholder marks the account index in a scan template.
+const AccountPlaceholder = "{a}"
+
+// DefaultPath is the standard Ethereum layout used by MetaMask and most
+// software wallets.
+const DefaultPath = "m/44'/60'/0'/0/{i}"
+
+// PathAliases are the well-known layouts accepted in place of a template.
+var PathAliases = map[string]string{
+	"bip44":  DefaultPath,
+	"ledger": "m/44'/60'/{i}'/0/0",  // Ledger Live: one account per index
+	"mew":    "m/44'/60'/0'/{i}",    // legacy MEW / Ledger Chrome app
+	"tron":   "m/44'/195'/0'/0/{i}", // TronLink and other Tron wallets
+	"cosmos": "m/44'/118'/0'/0/{i}", // Keplr and the Cosmos SDK, coin type 118
+}
+
+// ParsePathTemplates expands aliases and checks that every template contains
+// the index placeholder exactly once and yields a valid derivation path. No
+// templates means DefaultPath.
+func ParsePathTemplates(specs []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		t := strings.TrimSpace(spec)
+		if t == "" {
+			continue
+		}
+		if a, ok := PathAliases[strings.ToLower(t)]; ok {
+			t = a
+		}
+		pt, err := parseTemplate(t)
+		if err == nil && pt.hasAccount {
+			err = fmt.Errorf("%s is only supported by the scan mode", AccountPlaceholder)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("path template %q: %w", spec, err)
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		out = []string{DefaultPath}
+	}
+	return out, nil
+}
+
+func templatePath(template string, i int) string {
+	return strings.Replace(template, IndexPlaceholder, strconv.Itoa(i), 1)
+}
+
 type Derived struct {
 	Mnemonic string
 	Index    int
+	Template string
 	Path     string
 	Priv     *ecdsa.PrivateKey
 	Address  string
@@ -27,37 +86,39 @@ func NewMnemonic(strength int) (string, error) {
 	return bip39.NewMnemonic(entropy)
 }
 
+// Derive derives the first n addresses of mn along DefaultPath.
 func Derive(mn, passphrase string, n int) ([]Derived, error) {
+	return DeriveTemplates(mn, passphrase, []string{DefaultPath}, n)
+}
+
+// DeriveTemplates derives the indexes 0..n-1 of every template (see
+// ParsePathTemplates) from one seed, template by template.
+func DeriveTemplates(mn, passphrase string, templates []string, n int) ([]Derived, error) {
 	if n <= 0 {
 		n = 5
 	}
-	seed := bip39.NewSeed(mn, passphrase)
-	w, err := hdwallet.NewFromSeed(seed)
+	d, err := NewDeriver(templates)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]Derived, 0, n)
-	for i := 0; i < n; i++ {
-		pathStr := fmt.Sprintf("m/44'/60'/0'/0/%d", i)
-		path := hdwallet.MustParseDerivationPath(pathStr)
-		acct, err := w.Derive(path, true)
-		if err != nil {
-			return nil, err
-		}
-		addr, err := w.Address(acct)
-		if err != nil {
-			return nil, err
-		}
-		priv, err := w.PrivateKey(acct)
+	children, err := d.Derive(Seed(mn, passphrase), n)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Derived, 0, len(children))
+	for i := range children {
+		c := &children[i]
+		priv, err := c.PrivKey()
 		if err != nil {
 			return nil, err
 		}
 		out = append(out, Derived{
 			Mnemonic: mn,
-			Index:    i,
-			Path:     pathStr,
+			Index:    c.Index,
+			Template: c.Template,
+			Path:     c.Path(),
 			Priv:     priv,
-			Address:  addr.Hex(),
+			Address:  common.Address(c.Address).Hex(),
 		})
 	}
 	return out, nil
@@ -0,0 +1,193 @@
+package mnemonic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	mrand "math/rand/v2"
+	"slices"
+	"unicode/utf8"
+
+	"WalletTools/pkg/config"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MinBits is the least entropy a constrained phrase may keep without
+// allow_weak: the 112-bit security level, well above what a 12-word phrase
+// loses to a fixed first word (11 bits) or a short deny list.
+const MinBits = 112
+
+// maxSamplerTries bounds the draws for one phrase, in case the last word
+// admits almost no checksum.
+const maxSamplerTries = 1 << 20
+
+// Sampler draws phrases whose words satisfy a config.MnemonicPattern. Words
+// are picked from their allowed sets directly instead of filtering random
+// phrases; the entropy bits of the last word are drawn too and the draw is
+// repeated until the checksum makes that word an allowed one. Every valid
+// phrase is thus equally likely. A Sampler is safe for concurrent use.
+type Sampler struct {
+	wl      *Wordlist
+	words   int
+	allowed [][]int // word indexes per position
+	last    []bool  // allowed indexes of the last position
+}
+
+// NewSampler compiles p for phrases of strength bits in wl. Constraints that
+// leave less than MinBits are refused unless p allows weak phrases.
+func (wl *Wordlist) NewSampler(strength int, p *config.MnemonicPattern) (*Sampler, error) {
+	if !ValidStrength(strength) {
+		return nil, fmt.Errorf("invalid mnemonic strength %d", strength)
+	}
+	n := strength / 32 * 3
+
+	lookup := func(field, w string) (int, error) {
+		i, ok := wl.index[norm.NFKD.String(w)]
+		if !ok {
+			return 0, fmt.Errorf("mnemonic.%s: %q is not in the %s wordlist", field, w, wl.Lang)
+		}
+		return i, nil
+	}
+	base := make([]bool, len(wl.words))
+	if len(p.Allow) == 0 {
+		for i := range base {
+			base[i] = true
+		}
+	}
+	for _, w := range p.Allow {
+		i, err := lookup("allow", w)
+		if err != nil {
+			return nil, err
+		}
+		base[i] = true
+	}
+	for _, w := range p.Deny {
+		i, err := lookup("deny", w)
+		if err != nil {
+			return nil, err
+		}
+		base[i] = false
+	}
+	if p.MaxLength > 0 {
+		for i, w := range wl.words {
+			if utf8.RuneCountInString(w) > p.MaxLength {
+				base[i] = false
+			}
+		}
+	}
+	var all []int
+	for i, ok := range base {
+		if ok {
+			all = append(all, i)
+		}
+	}
+
+	s := &Sampler{wl: wl, words: n, allowed: make([][]int, n)}
+	for pos := range s.allowed {
+		s.allowed[pos] = all
+	}
+	for pos, w := range p.Fixed {
+		if pos > n {
+			return nil, fmt.Errorf("mnemonic.fixed: position %d is beyond %d words", pos, n)
+		}
+		i, err := lookup("fixed", w)
+		if err != nil {
+			return nil, err
+		}
+		if !base[i] {
+			return nil, fmt.Errorf("mnemonic.fixed: %q at position %d is excluded by allow, deny or max_length", w, pos)
+		}
+		s.allowed[pos-1] = []int{i}
+	}
+	for pos, a := range s.allowed {
+		if len(a) == 0 {
+			return nil, fmt.Errorf("mnemonic: no word is allowed at position %d", pos+1)
+		}
+	}
+	s.last = make([]bool, len(wl.words))
+	for _, i := range s.allowed[n-1] {
+		s.last[i] = true
+	}
+	if s.Bits() < MinBits && !p.AllowWeak {
+		return nil, fmt.Errorf("mnemonic constraints leave %.1f of %d bits of entropy, below %d; set mnemonic.allow_weak to accept such wallets",
+			s.Bits(), s.Strength(), MinBits)
+	}
+	return s, nil
+}
+
+// Bits is the entropy left to the phrases: log2 of how many valid phrases
+// satisfy the constraints, taking a 1/2^cs share of last words for the cs
+// checksum bits.
+func (s *Sampler) Bits() float64 {
+	bits := 0.0
+	for _, a := range s.allowed {
+		bits += math.Log2(float64(len(a)))
+	}
+	return bits - float64(s.words/3)
+}
+
+// Strength is the entropy of an unconstrained phrase of the same length.
+func (s *Sampler) Strength() int { return s.words / 3 * 32 }
+
+// New draws a phrase and returns it with its entropy.
+func (s *Sampler) New() (string, []byte, error) {
+	var seed [32]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return "", nil, err
+	}
+	r := mrand.New(mrand.NewChaCha8(seed))
+	clear(seed[:])
+
+	cs := s.words / 3
+	idx := make([]int, s.words)
+	entropy := make([]byte, s.Strength()/8)
+	for try := 0; try < maxSamplerTries; try++ {
+		clear(entropy)
+		for pos := 0; pos < s.words-1; pos++ {
+			a := s.allowed[pos]
+			idx[pos] = a[r.IntN(len(a))]
+		}
+		// The last word carries 11-cs entropy bits and cs checksum bits.
+		idx[s.words-1] = r.IntN(1 << (11 - cs))
+		for pos, v := range idx {
+			width := 11
+			if pos == s.words-1 {
+				width = 11 - cs
+			}
+			for j := 0; j < width; j++ {
+				if v>>(width-1-j)&1 == 1 {
+					b := pos*11 + j
+					entropy[b/8] |= 1 << (7 - b%8)
+				}
+			}
+		}
+		sum := sha256.Sum256(entropy)
+		last := idx[s.words-1]<<cs | int(sum[0]>>(8-cs))
+		if s.last[last] {
+			return s.wl.Encode(entropy), entropy, nil
+		}
+	}
+	return "", nil, errors.New("mnemonic constraints: no phrase found, the last word admits almost no checksum")
+}
+
+// Check verifies that a phrase's entropy satisfies the constraints.
+func (s *Sampler) Check(entropy []byte) error {
+	if len(entropy)*8 != s.Strength() {
+		return errors.New("mnemonic constraints: wrong entropy size")
+	}
+	sum := sha256.Sum256(entropy)
+	bits := append(append([]byte(nil), entropy...), sum[0])
+	for pos, a := range s.allowed {
+		v := 0
+		for b := pos * 11; b < pos*11+11; b++ {
+			v = v<<1 | int(bits[b/8]>>(7-b%8)&1)
+		}
+		if !slices.Contains(a, v) {
+			return fmt.Errorf("mnemonic constraints: word %d is not allowed", pos+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package mnemonic
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tyler-smith/go-bip39"
+
+	"WalletTools/pkg/config"
+)
+
+func english(t *testing.T) *Wordlist {
+	t.Helper()
+	wl, err := NewWordlist("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wl
+}
+
+// drawChecked draws n phrases from s and checks that each is a valid BIP-39
+// phrase that passes s.Check.
+func drawChecked(t *testing.T, wl *Wordlist, s *Sampler, n int) [][]string {
+	t.Helper()
+	var out [][]string
+	for range n {
+		mn, entropy, err := s.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Check(entropy); err != nil {
+			t.Fatalf("%q: %v", mn, err)
+		}
+		if err := wl.Verify(mn, entropy); err != nil {
+			t.Fatalf("%q: %v", mn, err)
+		}
+		if !bip39.IsMnemonicValid(mn) {
+			t.Fatalf("%q is not a valid BIP-39 phrase", mn)
+		}
+		out = append(out, strings.Fields(mn))
+	}
+	return out
+}
+
+func TestSamplerConstraints(t *testing.T) {
+	wl := english(t)
+	short := wl.Words()[:64]
+
+	tests := []struct {
+		name     string
+		strength int
+		p        config.MnemonicPattern
+		ok       func(pos int, w string) bool
+	}{
+		{"fixed first and last", 256, config.MnemonicPattern{Fixed: map[int]string{1: "zoo", 24: "zoo"}},
+			func(pos int, w string) bool { return pos != 0 && pos != 23 || w == "zoo" }},
+		{"deny", 128, config.MnemonicPattern{Deny: []string{"abandon", "about", "zoo"}},
+			func(_ int, w string) bool { return w != "abandon" && w != "about" && w != "zoo" }},
+		{"allow", 256, config.MnemonicPattern{Allow: short, AllowWeak: true},
+			func(_ int, w string) bool { return slices.Contains(short, w) }},
+		{"max_length", 256, config.MnemonicPattern{MaxLength: 4, AllowWeak: true},
+			func(_ int, w string) bool { return utf8.RuneCountInString(w) <= 4 }},
+	}
+	for _, tt := range tests {
+		s, err := wl.NewSampler(tt.strength, &tt.p)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, words := range drawChecked(t, wl, s, 50) {
+			if len(words) != tt.strength/32*3 {
+				t.Fatalf("%s: got %d words", tt.name, len(words))
+			}
+			for pos, w := range words {
+				if !tt.ok(pos, w) {
+					t.Errorf("%s: word %d %q breaks the constraint in %v", tt.name, pos+1, w, words)
+				}
+			}
+		}
+	}
+}
+
+func TestSamplerCheck(t *testing.T) {
+	wl := english(t)
+	s, err := wl.NewSampler(128, &config.MnemonicPattern{Fixed: map[int]string{1: "zoo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entropy := make([]byte, 16) // abandon … about
+	if err := s.Check(entropy); err == nil {
+		t.Error("abandon … about passed a fixed first word zoo")
+	}
+	entropy[0], entropy[1] = 0xff, 0xe0 // zoo abandon …
+	if err := s.Check(entropy); err != nil {
+		t.Error(err)
+	}
+	if err := s.Check(make([]byte, 32)); err == nil {
+		t.Error("24-word entropy passed a 12-word sampler")
+	}
+}
+
+func TestSamplerBits(t *testing.T) {
+	wl := english(t)
+	for _, strength := range []int{128, 160, 192, 224, 256} {
+		s, err := wl.NewSampler(strength, &config.MnemonicPattern{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Bits() != float64(strength) || s.Strength() != strength {
+			t.Errorf("unconstrained %d: got %g bits of %d", strength, s.Bits(), s.Strength())
+		}
+	}
+
+	// The README example: zoo first, no abandon or about.
+	s, err := wl.NewSampler(128, &config.MnemonicPattern{
+		Fixed: map[int]string{1: "zoo"},
+		Deny:  []string{"abandon", "about"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 128 - 11 - 11*math.Log2(2048.0/2046)
+	if math.Abs(s.Bits()-want) > 1e-9 {
+		t.Errorf("got %g bits, want %g", s.Bits(), want)
+	}
+	if got := fmt.Sprintf("%.0f", s.Bits()); got != "117" {
+		t.Errorf("got %s bits, the README says 117", got)
+	}
+}
+
+func TestSamplerWeak(t *testing.T) {
+	wl := english(t)
+	p := config.MnemonicPattern{Allow: wl.Words()[:64]} // 12×6-4 = 68 bits
+	if _, err := wl.NewSampler(128, &p); err == nil {
+		t.Fatal("68 bits accepted without allow_weak")
+	}
+	p.AllowWeak = true
+	s, err := wl.NewSampler(128, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Bits() != 68 {
+		t.Errorf("got %g bits, want 68", s.Bits())
+	}
+
+	// Two fixed words of 24 keep 256-22 bits, above the minimum.
+	if _, err := wl.NewSampler(256, &config.MnemonicPattern{Fixed: map[int]string{1: "zoo", 2: "zoo"}}); err != nil {
+		t.Error(err)
+	}
+	// Two of 12 leave 106.
+	if _, err := wl.NewSampler(128, &config.MnemonicPattern{Fixed: map[int]string{1: "zoo", 2: "zoo"}}); err == nil {
+		t.Error("106 bits accepted without allow_weak")
+	}
+}
+
+func TestNewSamplerErrors(t *testing.T) {
+	wl := english(t)
+	for name, p := range map[string]config.MnemonicPattern{
+		"unknown word":   {Fixed: map[int]string{1: "zzz"}},
+		"fixed denied":   {Fixed: map[int]string{1: "zoo"}, Deny: []string{"zoo"}},
+		"fixed too long": {Fixed: map[int]string{1: "abandon"}, MaxLength: 4},
+		"beyond words":   {Fixed: map[int]string{13: "zoo"}},
+		"nothing left":   {Allow: []string{"zoo"}, Deny: []string{"zoo"}, AllowWeak: true},
+	} {
+		if _, err := wl.NewSampler(128, &p); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package mnemonic
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"WalletTools/internal/crypto"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const hardened = 1 << 31
+
+var errInvalidChild = errors.New("bip32: invalid child key")
+
+// pathTemplate is a parsed template: the fixed components above the index,
+// the index component (hardened or not) and the fixed components below it.
+// Scan templates may also have an account component above the index; then
+// prefix ends above the account and middle leads from it to the index.
+type pathTemplate struct {
+	template   string
+	prefix     []uint32
+	hasAccount bool
+	account    uint32 // 0 or hardened
+	middle     []uint32
+	index      uint32 // 0 or hardened
+	suffix     []uint32
+}
+
+func parseTemplate(t string) (pathTemplate, error) {
+	pt := pathTemplate{template: t}
+	comps := strings.Split(t, "/")
+	if len(comps) < 2 || comps[0] != "m" {
+		return pt, errors.New(`must start with "m/"`)
+	}
+	cur := &pt.prefix
+	seen := false
+	for _, c := range comps[1:] {
+		h := strings.HasSuffix(c, "'")
+		c = strings.TrimSuffix(c, "'")
+		var off uint32
+		if h {
+			off = hardened
+		}
+		switch c {
+		case IndexPlaceholder:
+			if seen {
+				return pt, fmt.Errorf("needs exactly one %s", IndexPlaceholder)
+			}
+			seen = true
+			pt.index = off
+			cur = &pt.suffix
+			continue
+		case AccountPlaceholder:
+			if pt.hasAccount || seen {
+				return pt, fmt.Errorf("%s must appear once, above %s", AccountPlaceholder, IndexPlaceholder)
+			}
+			pt.hasAccount = true
+			pt.account = off
+			cur = &pt.middle
+			continue
+		}
+		v, err := strconv.ParseUint(c, 10, 31)
+		if err != nil {
+			return pt, fmt.Errorf("invalid component %q", c)
+		}
+		*cur = append(*cur, uint32(v)+off)
+	}
+	if !seen {
+		return pt, fmt.Errorf("needs exactly one %s", IndexPlaceholder)
+	}
+	return pt, nil
+}
+
+// node is a BIP-32 extended private key. The compressed public key, which
+// every non-hardened child needs, is computed once on first use.
+type node struct {
+	key    secp256k1.ModNScalar
+	chain  [32]byte
+	pub    [33]byte
+	hasPub bool
+}
+
+func masterNode(seed []byte) (node, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	var n node
+	if n.key.SetByteSlice(sum[:32]) || n.key.IsZero() {
+		return n, errInvalidChild
+	}
+	copy(n.chain[:], sum[32:])
+	return n, nil
+}
+
+func (n *node) pubKey() []byte {
+	if !n.hasPub {
+		var p secp256k1.JacobianPoint
+		secp256k1.ScalarBaseMultNonConst(&n.key, &p)
+		p.ToAffine()
+		copy(n.pub[:], secp256k1.NewPublicKey(&p.X, &p.Y).SerializeCompressed())
+		n.hasPub = true
+	}
+	return n.pub[:]
+}
+
+func (n *node) child(i uint32) (node, error) {
+	var data [37]byte
+	if i >= hardened {
+		kb := n.key.Bytes()
+		copy(data[1:33], kb[:])
+	} else {
+		copy(data[:33], n.pubKey())
+	}
+	binary.BigEndian.PutUint32(data[33:], i)
+
+	mac := hmac.New(sha512.New, n.chain[:])
+	mac.Write(data[:])
+	sum := mac.Sum(nil)
+	var c node
+	if c.key.SetByteSlice(sum[:32]) {
+		return c, errInvalidChild
+	}
+	c.key.Add(&n.key)
+	if c.key.IsZero() {
+		return c, errInvalidChild
+	}
+	copy(c.chain[:], sum[32:])
+	return c, nil
+}
+
+// Child is one derived address. Its private key is only materialized by
+// PrivKey, for hits.
+type Child struct {
+	Template string
+	Account  int // scan templates only
+	Index    int
+	Address  [20]byte
+
+	key secp256k1.ModNScalar
+}
+
+// Path is the full derivation path of c.
+func (c *Child) Path() string {
+	p := templatePath(c.Template, c.Index)
+	return strings.Replace(p, AccountPlaceholder, strconv.Itoa(c.Account), 1)
+}
+
+func (c *Child) PrivKey() (*ecdsa.PrivateKey, error) {
+	kb := c.key.Bytes()
+	defer clear(kb[:])
+	return gethcrypto.ToECDSA(kb[:])
+}
+
+// Deriver derives addresses along path templates. Per seed the node above
+// the index of every template is derived once; each index then only costs
+// the steps from the index component down. A Deriver reuses its buffers and
+// is not safe for concurrent use.
+type Deriver struct {
+	templates []pathTemplate
+	children  []Child
+	addresser
+}
+
+// NewDeriver takes templates as returned by ParsePathTemplates.
+func NewDeriver(templates []string) (*Deriver, error) {
+	if len(templates) == 0 {
+		templates = []string{DefaultPath}
+	}
+	d := &Deriver{addresser: newAddresser()}
+	for _, t := range templates {
+		pt, err := parseTemplate(t)
+		if err != nil {
+			return nil, fmt.Errorf("path template %q: %w", t, err)
+		}
+		d.templates = append(d.templates, pt)
+	}
+	return d, nil
+}
+
+// Derive returns the indexes 0..n-1 of every template, template by
+// template. The returned slice is reused by the next call.
+func (d *Deriver) Derive(seed []byte, n int) ([]Child, error) {
+	master, err := masterNode(seed)
+	if err != nil {
+		return nil, err
+	}
+	d.children = d.children[:0]
+	for _, pt := range d.templates {
+		parent := master
+		for _, c := range pt.prefix {
+			if parent, err = parent.child(c); err != nil {
+				return nil, err
+			}
+		}
+		for i := 0; i < n; i++ {
+			k, err := parent.child(pt.index + uint32(i))
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range pt.suffix {
+				if k, err = k.child(c); err != nil {
+					return nil, err
+				}
+			}
+			d.children = append(d.children, Child{
+				Template: pt.template,
+				Index:    i,
+				Address:  d.address(&k.key),
+				key:      k.key,
+			})
+		}
+	}
+	return d.children, nil
+}
+
+// addresser turns private keys into addresses, reusing its buffer and hash
+// state.
+type addresser struct {
+	buf     [64]byte
+	hash    gethcrypto.KeccakState
+	keyHash bool // set by UseKeyHash
+}
+
+func newAddresser() addresser {
+	return addresser{hash: gethcrypto.NewKeccakState()}
+}
+
+// UseKeyHash makes the derived addresses key hashes, the Hash160 of the
+// compressed public keys, in place of EVM addresses.
+func (d *addresser) UseKeyHash() { d.keyHash = true }
+
+func (d *addresser) address(k *secp256k1.ModNScalar) [20]byte {
+	var p secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(k, &p)
+	p.ToAffine()
+	if d.keyHash {
+		d.buf[0] = 0x02
+		if p.Y.IsOdd() {
+			d.buf[0] = 0x03
+		}
+		p.X.PutBytesUnchecked(d.buf[1:33])
+		return crypto.Hash160(d.buf[:33])
+	}
+	p.X.PutBytesUnchecked(d.buf[:32])
+	p.Y.PutBytesUnchecked(d.buf[32:])
+	var h [32]byte
+	d.hash.Reset()
+	d.hash.Write(d.buf[:])
+	d.hash.Read(h[:])
+	var a [20]byte
+	copy(a[:], h[12:])
+	return a
+}
@@ -0,0 +1,189 @@
+package mnemonic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const abandonAbout = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// decodeXprv returns the chain code and private key of a serialized
+// extended private key.
+func decodeXprv(t *testing.T, s string) (chain, key []byte) {
+	t.Helper()
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	n := new(big.Int)
+	for _, c := range s {
+		i := strings.IndexRune(alphabet, c)
+		if i < 0 {
+			t.Fatalf("%s: bad base58 character %q", s, c)
+		}
+		n.Mul(n, big.NewInt(58)).Add(n, big.NewInt(int64(i)))
+	}
+	b := n.FillBytes(make([]byte, 82)) // 78 bytes and a 4-byte checksum
+	return b[13:45], b[46:78]
+}
+
+// The test vectors 1 and 2 of BIP-32.
+func TestBIP32Vectors(t *testing.T) {
+	const h = hardened
+	tests := []struct {
+		seed string
+		path []uint32
+		xprv string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", nil,
+			"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"},
+		{"000102030405060708090a0b0c0d0e0f", []uint32{h},
+			"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"},
+		{"000102030405060708090a0b0c0d0e0f", []uint32{h, 1},
+			"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs"},
+		{"000102030405060708090a0b0c0d0e0f", []uint32{h, 1, h + 2},
+			"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM"},
+		{"000102030405060708090a0b0c0d0e0f", []uint32{h, 1, h + 2, 2},
+			"xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334"},
+		{"000102030405060708090a0b0c0d0e0f", []uint32{h, 1, h + 2, 2, 1000000000},
+			"xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76"},
+
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", nil,
+			"xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", []uint32{0},
+			"xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", []uint32{0, h + 2147483647},
+			"xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", []uint32{0, h + 2147483647, 1},
+			"xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", []uint32{0, h + 2147483647, 1, h + 2147483646},
+			"xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", []uint32{0, h + 2147483647, 1, h + 2147483646, 2},
+			"xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j"},
+	}
+	for _, tt := range tests {
+		seed, _ := hex.DecodeString(tt.seed)
+		n, err := masterNode(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range tt.path {
+			if n, err = n.child(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+		chain, key := decodeXprv(t, tt.xprv)
+		kb := n.key.Bytes()
+		if !bytes.Equal(n.chain[:], chain) || !bytes.Equal(kb[:], key) {
+			t.Errorf("seed %.8s… path %v: got chain %x key %x, want %x %x", tt.seed, tt.path, n.chain, kb, chain, key)
+		}
+	}
+}
+
+func TestDeriveTemplates(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string // addresses at the indexes 0, 1, …
+	}{
+		{"bip44", []string{
+			"0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+			"0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0",
+		}},
+		{"ledger", []string{
+			"0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+			"0x78839F6054d7ed13918bAe0473BA31b1Ca9D7265",
+			"0x07B5FdfEB4E11826D233403Fe8Db0611CCF4c231",
+		}},
+		{"mew", []string{
+			"0xB8Fd42000d00202DCbCF5e18d6640d656345FD6A",
+			"0x94381955F4028159A477a107510618aDb6B79Eb7",
+		}},
+		{"tron", []string{
+			"0xC8599111F29c1e1E061265b4AF93eA1F274aD78A",
+			"0xb6E708a39781c96Bd399C7657780Ff9Fe9F052A8",
+		}},
+	}
+	for _, tt := range tests {
+		templates, err := ParsePathTemplates([]string{tt.template})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := DeriveTemplates(abandonAbout, "", templates, len(tt.want))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range tt.want {
+			if got[i].Address != want {
+				t.Errorf("%s index %d (%s): got %s, want %s", tt.template, i, got[i].Path, got[i].Address, want)
+			}
+			if a := gethcrypto.PubkeyToAddress(got[i].Priv.PublicKey).Hex(); a != want {
+				t.Errorf("%s index %d: private key gives %s, want %s", tt.template, i, a, want)
+			}
+		}
+	}
+}
+
+// The scanner walks the same tree as the deriver.
+func TestScannerMatchesDeriver(t *testing.T) {
+	s, err := NewScanner(Seed(abandonAbout, ""), DefaultScanPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		account, index int
+		want           string
+	}{
+		{0, 0, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{0, 1, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+		{1, 0, "0x78839F6054d7ed13918bAe0473BA31b1Ca9D7265"},
+		{2, 0, "0x07B5FdfEB4E11826D233403Fe8Db0611CCF4c231"},
+	}
+	for _, tt := range tests {
+		c, err := s.Derive(tt.account, tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := common.Address(c.Address).Hex(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", c.Path(), got, tt.want)
+		}
+	}
+}
+
+// With key hashes the deriver yields what Cosmos addresses encode: the first
+// Keplr account of "abandon … about" is cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4.
+func TestDeriverKeyHash(t *testing.T) {
+	templates, err := ParsePathTemplates([]string{"cosmos"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDeriver(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.UseKeyHash()
+	children, err := d.Derive(Seed(abandonAbout, ""), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(children[0].Address[:]), "28ff5c6d57d8cfd492b6fb42614536ed648e01fd"; got != want {
+		t.Errorf("%s: got key hash %s, want %s", children[0].Path(), got, want)
+	}
+	for _, c := range children {
+		kb := c.key.Bytes()
+		priv, err := gethcrypto.ToECDSA(kb[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sha := sha256.Sum256(gethcrypto.CompressPubkey(&priv.PublicKey))
+		h := ripemd160.New()
+		h.Write(sha[:])
+		if want := h.Sum(nil); !bytes.Equal(c.Address[:], want) {
+			t.Errorf("%s: got key hash %x, want %x", c.Path(), c.Address, want)
+		}
+	}
+}
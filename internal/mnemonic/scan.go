@@ -0,0 +1,102 @@
+package mnemonic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultScanPath is the scan template for MetaMask-style wallets: accounts
+// at {a}, addresses at {i}.
+const DefaultScanPath = "m/44'/60'/{a}'/0/{i}"
+
+// MaxScanIndex bounds accounts and indexes: both are a single BIP-32
+// component below the hardened offset.
+const MaxScanIndex = hardened
+
+// Scanner derives the addresses of one fixed seed along a scan template with
+// an index placeholder {i} and an optional account placeholder {a} above it.
+// The node above the account is derived once, the node above the index once
+// per account, so an index costs one child step plus the suffix. A Scanner
+// is not safe for concurrent use; workers share the seed, not the Scanner.
+type Scanner struct {
+	pt      pathTemplate
+	base    node
+	parent  node
+	account int // account of parent, -1 before the first Derive
+	addresser
+}
+
+// NewScanner expands aliases like ParsePathTemplates but also accepts {a};
+// an empty template means DefaultScanPath.
+func NewScanner(seed []byte, template string) (*Scanner, error) {
+	if template == "" {
+		template = DefaultScanPath
+	}
+	if a, ok := PathAliases[strings.ToLower(template)]; ok {
+		template = a
+	}
+	pt, err := parseTemplate(template)
+	if err != nil {
+		return nil, fmt.Errorf("scan template %q: %w", template, err)
+	}
+	base, err := masterNode(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range pt.prefix {
+		if base, err = base.child(c); err != nil {
+			return nil, err
+		}
+	}
+	return &Scanner{pt: pt, base: base, account: -1, addresser: newAddresser()}, nil
+}
+
+// Template is the parsed scan template.
+func (s *Scanner) Template() string { return s.pt.template }
+
+// HasAccount reports whether the template has an account placeholder.
+func (s *Scanner) HasAccount() bool { return s.pt.hasAccount }
+
+// Derive returns the address at account and index. Consecutive calls with
+// the same account reuse its node; account must be 0 without {a}.
+func (s *Scanner) Derive(account, index int) (Child, error) {
+	if account < 0 || account >= MaxScanIndex || index < 0 || index >= MaxScanIndex {
+		return Child{}, errors.New("scan position out of range")
+	}
+	if account != s.account {
+		if account != 0 && !s.pt.hasAccount {
+			return Child{}, fmt.Errorf("scan template %q has no %s", s.pt.template, AccountPlaceholder)
+		}
+		p := s.base
+		var err error
+		if s.pt.hasAccount {
+			if p, err = p.child(s.pt.account + uint32(account)); err != nil {
+				return Child{}, err
+			}
+		}
+		for _, c := range s.pt.middle {
+			if p, err = p.child(c); err != nil {
+				return Child{}, err
+			}
+		}
+		s.parent, s.account = p, account
+	}
+
+	k, err := s.parent.child(s.pt.index + uint32(index))
+	if err != nil {
+		return Child{}, err
+	}
+	for _, c := range s.pt.suffix {
+		if k, err = k.child(c); err != nil {
+			return Child{}, err
+		}
+	}
+	return Child{
+		Template: s.pt.template,
+		Account:  account,
+		Index:    index,
+		Address:  s.address(&k.key),
+		key:      k.key,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package mnemonic
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	bip39 "github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultLang is the wordlist used when none is selected.
+const DefaultLang = "english"
+
+// languages are the official BIP-39 wordlists.
+var languages = map[string][]string{
+	"english":             wordlists.English,
+	"japanese":            wordlists.Japanese,
+	"korean":              wordlists.Korean,
+	"spanish":             wordlists.Spanish,
+	"french":              wordlists.French,
+	"italian":             wordlists.Italian,
+	"czech":               wordlists.Czech,
+	"chinese_simplified":  wordlists.ChineseSimplified,
+	"chinese_traditional": wordlists.ChineseTraditional,
+}
+
+// Languages lists the available wordlists, sorted.
+func Languages() []string {
+	out := make([]string, 0, len(languages))
+	for l := range languages {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ValidStrength reports whether bits is a BIP-39 entropy size: 128, 160,
+// 192, 224 or 256 bits for 12, 15, 18, 21 or 24 words.
+func ValidStrength(bits int) bool {
+	return bits >= 128 && bits <= 256 && bits%32 == 0
+}
+
+// Wordlist encodes entropy into phrases of one language. Unlike
+// bip39.SetWordList it holds no global state, so runs with different
+// languages do not interfere.
+type Wordlist struct {
+	Lang  string
+	words []string
+	index map[string]int // NFKD form of each word
+	sep   string
+}
+
+func NewWordlist(lang string) (*Wordlist, error) {
+	if lang == "" {
+		lang = DefaultLang
+	}
+	words, ok := languages[lang]
+	if !ok {
+		return nil, fmt.Errorf("unknown wordlist %q (have %s)", lang, strings.Join(Languages(), ", "))
+	}
+	wl := &Wordlist{Lang: lang, words: words, index: make(map[string]int, len(words)), sep: " "}
+	if lang == "japanese" {
+		wl.sep = "　" // ideographic space, as the spec prescribes
+	}
+	for i, w := range words {
+		wl.index[norm.NFKD.String(w)] = i
+	}
+	return wl, nil
+}
+
+// Words is the 2048-word list itself; callers must not modify it.
+func (wl *Wordlist) Words() []string { return wl.words }
+
+// New draws strength bits of entropy and returns its phrase together with
+// the entropy.
+func (wl *Wordlist) New(strength int) (string, []byte, error) {
+	if strength == 0 {
+		strength = 128
+	}
+	if !ValidStrength(strength) {
+		return "", nil, fmt.Errorf("invalid mnemonic strength %d", strength)
+	}
+	entropy := make([]byte, strength/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", nil, err
+	}
+	return wl.Encode(entropy), entropy, nil
+}
+
+// Encode is the phrase of entropy: its bits followed by the first len/32 bits
+// of its sha256, in 11-bit word indexes.
+func (wl *Wordlist) Encode(entropy []byte) string {
+	sum := sha256.Sum256(entropy)
+	bits := append(append([]byte(nil), entropy...), sum[0])
+	n := (len(entropy)*8 + len(entropy)/4) / 11
+
+	out := make([]string, n)
+	for i := 0; i < n; i++ {
+		idx := 0
+		for b := i * 11; b < i*11+11; b++ {
+			idx = idx<<1 | int(bits[b/8]>>(7-b%8)&1)
+		}
+		out[i] = wl.words[idx]
+	}
+	return strings.Join(out, wl.sep)
+}
+
+// Decode recovers the entropy of a phrase and checks its checksum.
+func (wl *Wordlist) Decode(mn string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mn))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("mnemonic has %d words", len(words))
+	}
+	bits := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		idx, ok := wl.index[w]
+		if !ok {
+			return nil, fmt.Errorf("word %d is not in the %s wordlist", i+1, wl.Lang)
+		}
+		for j := 0; j < 11; j++ {
+			if idx>>(10-j)&1 == 1 {
+				b := i*11 + j
+				bits[b/8] |= 1 << (7 - b%8)
+			}
+		}
+	}
+	entropy := bits[:len(words)*4/3]
+	sum := sha256.Sum256(entropy)
+	for j := 0; j < len(words)/3; j++ {
+		b := len(entropy)*8 + j
+		if bits[b/8]>>(7-b%8)&1 != sum[0]>>(7-j)&1 {
+			return nil, errors.New("mnemonic checksum mismatch")
+		}
+	}
+	return entropy, nil
+}
+
+// Verify checks that mn round-trips through the wordlist to entropy.
+func (wl *Wordlist) Verify(mn string, entropy []byte) error {
+	got, err := wl.Decode(mn)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, entropy) {
+		return errors.New("mnemonic does not encode its entropy")
+	}
+	return nil
+}
+
+// DetectWordlist finds the wordlist a phrase decodes in, checksum included.
+// With lang set only that one is tried.
+func DetectWordlist(mn, lang string) (*Wordlist, error) {
+	if lang != "" {
+		wl, err := NewWordlist(lang)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := wl.Decode(mn); err != nil {
+			return nil, err
+		}
+		return wl, nil
+	}
+	for _, l := range Languages() {
+		wl, _ := NewWordlist(l)
+		if _, err := wl.Decode(mn); err == nil {
+			return wl, nil
+		}
+	}
+	return nil, errors.New("mnemonic is not a valid BIP-39 phrase in any wordlist")
+}
+
+// Seed is the BIP-39 seed of mn. Phrase and passphrase are NFKD-normalized
+// first, which matters for every wordlist but English.
+func Seed(mn, passphrase string) []byte {
+	return bip39.NewSeed(norm.NFKD.String(mn), norm.NFKD.String(passphrase))
+}
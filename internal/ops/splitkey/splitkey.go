@@ -0,0 +1,170 @@
+package splitkey
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"WalletTools/internal/crypto"
+	"WalletTools/internal/keystore"
+	"WalletTools/internal/logsink"
+	"WalletTools/pkg/logx"
+)
+
+// CombineOptions controls a combine job.
+type CombineOptions struct {
+	InputsBaseDir        string            // e.g. "inputs"
+	LogsBase             string            // e.g. "logs"
+	Secret               *ecdsa.PrivateKey // the owner's secret, never leaves this machine
+	Password             string            // optional: also write keystores
+	HideSecretsInConsole bool
+}
+
+// partialRecord is one line of a split search result file.
+type partialRecord struct {
+	Address    string `json:"address"`
+	EVMAddress string `json:"evm_address"` // searches of other address formats
+	PartialKey string `json:"partial_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+// CombinePartials reads the split search results from inputs/combine/*.jsonl,
+// adds each partial key to the owner's secret and keeps the final key only if
+// its address is the one the search found. Results:
+//
+//	logs/combine/<DD.MM.YYYY>/combine_<HH-MM-SS>/app.log
+//	logs/combine/.../all.txt ("address:private" lines)
+//	logs/combine/.../all.jsonl (keystores, only with a password)
+func CombinePartials(ctx context.Context, opt CombineOptions) error {
+	const module = "combine"
+
+	dir, err := logsink.MakeModuleDirs(opt.LogsBase, module, false)
+	if err != nil {
+		return err
+	}
+	logPath := filepath.Join(dir, "app.log")
+	if err := logx.Init(logx.Config{Level: "info", FilePath: logPath, ConsoleOnly: false, HideSecretsInConsole: opt.HideSecretsInConsole}); err != nil {
+		return fmt.Errorf("logx init failed: %w", err)
+	}
+	defer logx.Close()
+	app := logx.S()
+
+	owner := crypto.PubKeyHex(opt.Secret)
+	inDir := filepath.Join(opt.InputsBaseDir, module)
+	files, _ := filepath.Glob(filepath.Join(inDir, "*.jsonl"))
+	app.Infow("combine started", "inputs", inDir, "out", dir, "files", len(files), "owner_public_key", owner)
+	if len(files) == 0 {
+		app.Warnw("no split results found", "dir", inDir)
+		return nil
+	}
+
+	outF, err := os.OpenFile(filepath.Join(dir, "all.txt"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create all.txt: %w", err)
+	}
+	defer outF.Close()
+	allPath := filepath.Join(dir, "all.jsonl")
+
+	var total, okCnt, failCnt int
+	start := time.Now()
+
+	for _, p := range files {
+		f, err := os.Open(p)
+		if err != nil {
+			app.Errorw("open jsonl failed", "file", p, "err", err)
+			continue
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if ctx.Err() != nil {
+				_ = f.Close()
+				return ctx.Err()
+			}
+			line := strings.TrimSpace(sc.Text())
+			if line == "" {
+				continue
+			}
+			total++
+
+			priv, addr, cerr := combineOne([]byte(line), opt.Secret, owner)
+			if cerr != nil {
+				failCnt++
+				app.Errorw("combine failed", "file", p, "err", cerr)
+				continue
+			}
+			privHex := crypto.PrivToHex(priv)
+			if _, err := fmt.Fprintf(outF, "%s:%s\n", addr, privHex); err != nil {
+				app.Errorw("write all.txt failed", "addr", addr, "err", err)
+			}
+			if opt.Password != "" {
+				blob, kerr := crypto.KeystoreJSON(priv, opt.Password)
+				if kerr == nil {
+					kerr = keystore.AppendJSONL(allPath, blob)
+				}
+				if kerr != nil {
+					app.Errorw("keystore write failed", "addr", addr, "err", kerr)
+				}
+			}
+
+			okCnt++
+			if !opt.HideSecretsInConsole {
+				app.Infow("COMBINED", "address", addr, "private_key", privHex)
+			} else {
+				app.Infow("COMBINED", "address", addr)
+			}
+		}
+		_ = f.Close()
+		if err := sc.Err(); err != nil {
+			app.Errorw("scan jsonl failed", "file", p, "err", err)
+		}
+	}
+
+	app.Infow("combine finished", "total", total, "ok", okCnt, "failed", failCnt, "elapsed", time.Since(start).String())
+	return nil
+}
+
+// combineOne assembles the final key of one result line and verifies that it
+// belongs to the recorded address (its EVM form, when the search used
+// another format).
+func combineOne(line []byte, secret *ecdsa.PrivateKey, owner string) (*ecdsa.PrivateKey, string, error) {
+	var rec partialRecord
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, "", fmt.Errorf("invalid result json: %w", err)
+	}
+	if rec.PublicKey != "" {
+		pub, err := crypto.ParsePubKey(rec.PublicKey)
+		if err != nil {
+			return nil, rec.Address, err
+		}
+		if pub.X.Cmp(secret.PublicKey.X) != 0 || pub.Y.Cmp(secret.PublicKey.Y) != 0 {
+			return nil, rec.Address, fmt.Errorf("address %s was searched for public key %s, not %s", rec.Address, rec.PublicKey, owner)
+		}
+	}
+	partial, err := hex.DecodeString(strings.TrimPrefix(rec.PartialKey, "0x"))
+	if err != nil {
+		return nil, rec.Address, fmt.Errorf("partial key: %w", err)
+	}
+	priv, err := crypto.CombineSplitKey(secret, partial)
+	if err != nil {
+		return nil, rec.Address, err
+	}
+	want := rec.Address
+	if rec.EVMAddress != "" {
+		want = rec.EVMAddress
+	}
+	addr := crypto.AddressHex(priv)
+	if !strings.EqualFold(addr, want) {
+		return nil, rec.Address, fmt.Errorf("combined key gives %s, want %s", addr, want)
+	}
+	if rec.EVMAddress != "" {
+		return priv, rec.Address, nil
+	}
+	return priv, addr, nil
+}
@@ -0,0 +1,208 @@
+package patterns
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+)
+
+// Regexps and rules are tried on random addresses until they have matched
+// sampleMinHits times or sampleMaxSamples addresses were tried.
+const (
+	sampleMinHits    = 64
+	sampleMaxSamples = 1 << 20
+)
+
+// Estimate is the probability that a single random address matches one
+// pattern. Attempts until a hit are geometrically distributed, so the
+// expected count is 1/P and the search is memoryless: the estimate for the
+// remaining time does not shrink with the time already spent.
+type Estimate struct {
+	Kind  string
+	Index int
+	Name  string // of a rule
+	P     float64
+
+	// Empirical estimates come from sampling. With no hit among Samples,
+	// only an upper bound is known: P is then 1/Samples and Bound is set,
+	// which makes the attempt counts lower bounds.
+	Empirical bool
+	Samples   int
+	Bound     bool
+
+	// CaseBits is the number of letters whose EIP-55 case a specific or
+	// mask pattern pins under checksum_case: P carries a factor of
+	// 2^-CaseBits.
+	CaseBits int
+
+	id int
+}
+
+func (e Estimate) Label() string {
+	if e.Name != "" {
+		return "rules[" + e.Name + "]"
+	}
+	return fmt.Sprintf("%s[%d]", e.Kind, e.Index)
+}
+
+// Expected is the mean number of attempts until a hit.
+func (e Estimate) Expected() float64 {
+	if e.P <= 0 {
+		return math.Inf(1)
+	}
+	return 1 / e.P
+}
+
+// Attempts is the number of attempts after which a hit has happened with
+// probability q.
+func (e Estimate) Attempts(q float64) float64 {
+	if e.P <= 0 {
+		return math.Inf(1)
+	}
+	return -math.Log1p(-q) / e.P
+}
+
+// Estimates returns one Estimate per compiled pattern, in matching order.
+func (m *Matcher) Estimates() []Estimate {
+	var out []Estimate
+
+	for i := range m.rules {
+		p := &m.rules[i]
+		e := sampled(m.sample(p.expr.eval))
+		e.Kind, e.Index, e.Name, e.id = p.kind, i, p.name, p.id
+		out = append(out, e)
+	}
+
+	for i := range m.symmetric {
+		p := &m.symmetric[i]
+		out = append(out, Estimate{Kind: "symmetric", Index: i, P: m.placeholderProb(p), id: p.id})
+	}
+
+	for i := range m.placeholder {
+		p := &m.placeholder[i]
+		out = append(out, Estimate{Kind: "placeholder", Index: i, P: m.placeholderProb(p), id: p.id})
+	}
+
+	for i, p := range m.specific {
+		e := Estimate{Kind: "specific", Index: i, id: p.id}
+		if !p.never {
+			e.P = m.literalProb(p.strPre, true) * m.literalProb(p.strSuf, false)
+		}
+		if m.checksum {
+			e.CaseBits = letters(p.strPre) + letters(p.strSuf)
+		}
+		out = append(out, e)
+	}
+
+	for i := range m.mask {
+		p := &m.mask[i]
+		e := Estimate{Kind: "mask", Index: i, id: p.id}
+		e.P, e.CaseBits = m.maskProb(p)
+		out = append(out, e)
+	}
+
+	for i := range m.words {
+		p := &m.words[i]
+		out = append(out, Estimate{Kind: "words", Index: i, P: m.wordsProb(p), id: p.id})
+	}
+
+	if e := m.edges; e != nil {
+		pr := 0.0
+		if e.prefix {
+			pr = m.runProb(e.minCount, true)
+		}
+		if e.suffix {
+			side := m.runProb(e.minCount, false)
+			pr = pr + side - pr*side
+		}
+		out = append(out, Estimate{Kind: "edges", Index: 0, P: pr, id: e.id})
+	}
+
+	for i := range m.regexp {
+		rp := &m.regexp[i]
+		e := sampled(m.sample(rp.test))
+		e.Kind, e.Index, e.id = "regexp", i, rp.id
+		out = append(out, e)
+	}
+
+	if p := m.phrase; p != nil {
+		out = append(out, Estimate{Kind: "mnemonic", Index: 0, P: 1, id: p.id})
+	}
+	return out
+}
+
+// sample counts the random addresses test accepts.
+func (m *Matcher) sample(test func(v *view) bool) (hits, n int) {
+	size := m.format.PayloadLen()
+	var buf [1024 * 32]byte
+	for hits < sampleMinHits && n < sampleMaxSamples {
+		_, _ = rand.Read(buf[:])
+		for j := 0; j+size <= len(buf); j += size {
+			v := m.view(buf[j : j+size])
+			if test(&v) {
+				hits++
+			}
+		}
+		n += len(buf) / size
+	}
+	return hits, n
+}
+
+// Enabled reports whether the pattern behind e has not yet been disabled by
+// its quota.
+func (m *Matcher) Enabled(e Estimate) bool { return !m.off[e.id].Load() }
+
+// sampled is the estimate of hits matches among n samples.
+func sampled(hits, n int) Estimate {
+	if hits == 0 {
+		return Estimate{P: 1 / float64(n), Empirical: true, Samples: n, Bound: true}
+	}
+	return Estimate{P: float64(hits) / float64(n), Empirical: true, Samples: n}
+}
+
+// Combined is the probability that an address matches at least one pattern,
+// an upper bound if that of any pattern is.
+func Combined(ests []Estimate) Estimate {
+	c := Estimate{Kind: "any"}
+	miss := 1.0
+	for _, e := range ests {
+		miss *= 1 - e.P
+		c.Bound = c.Bound || e.Bound
+	}
+	c.P = 1 - miss
+	return c
+}
+
+// literalProb is the chance that fixed hex digits s appear at fixed
+// positions. With checksum each letter also has to hit its EIP-55 case,
+// which is one more bit of the keccak hash.
+func literalProb(s string, checksum bool) float64 {
+	pr := math.Pow(16, -float64(len(s)))
+	if checksum {
+		pr *= math.Pow(2, -float64(letters(s)))
+	}
+	return pr
+}
+
+// runProb is the chance that n adjacent digits repeat one (any) symbol.
+// Under checksum a letter symbol must also keep one case throughout.
+func runProb(n int, checksum bool) float64 {
+	if n <= 1 {
+		return 1
+	}
+	pr := math.Pow(16, -float64(n-1))
+	if checksum {
+		pr *= 10.0/16 + 6.0/16*math.Pow(2, -float64(n-1))
+	}
+	return pr
+}
+
+func letters(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c >= 'a' && c <= 'f' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package patterns
+
+import (
+	"math"
+	"testing"
+
+	"WalletTools/pkg/config"
+)
+
+func TestEstimates(t *testing.T) {
+	m, err := New(&config.PatternsConfig{
+		Format:   config.FormatEVM,
+		Specific: []config.SpecificPattern{{Prefix: "dead"}},
+		Regexp: []config.RegexpPattern{
+			{Pattern: "^0"},
+			{Pattern: "face.{0,30}beef"}, // rarer than the sampling can see
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ests := m.Estimates()
+	if len(ests) != 3 {
+		t.Fatalf("got %d estimates, want 3", len(ests))
+	}
+	spec, common, rare := ests[0], ests[1], ests[2]
+
+	if spec.P != math.Pow(16, -4) || spec.Empirical || spec.Bound {
+		t.Errorf("specific: got %+v", spec)
+	}
+	if !common.Empirical || common.Bound || math.Abs(common.P-1.0/16) > 0.02 {
+		t.Errorf("regexp ^0: got %+v", common)
+	}
+	if !rare.Empirical || !rare.Bound || rare.P != 1/float64(rare.Samples) {
+		t.Errorf("rare regexp: got %+v, want the upper bound 1/Samples", rare)
+	}
+	if math.IsInf(rare.Expected(), 1) || rare.Expected() != float64(rare.Samples) {
+		t.Errorf("rare regexp: expected %g attempts, want a lower bound of %d", rare.Expected(), rare.Samples)
+	}
+
+	c := Combined(ests)
+	if !c.Bound || c.P <= common.P {
+		t.Errorf("combined: got %+v", c)
+	}
+	if c := Combined(ests[:2]); c.Bound {
+		t.Errorf("combined without the rare regexp: got a bound")
+	}
+}
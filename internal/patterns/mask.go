@@ -0,0 +1,205 @@
+package patterns
+
+import (
+	"math/bits"
+	"strings"
+
+	"WalletTools/pkg/config"
+)
+
+// maskRule is a compiled mask template. For EVM the template becomes nibble
+// tables over the raw address: literal digits fold into one nibbleMask and
+// classes keep a 16-bit set of allowed nibbles per position; with
+// checksum_case the letters are then checked on the EIP-55 casing. Text
+// formats check every pinned position on the rendered body, which must have
+// the template's length.
+type maskRule struct {
+	nib     nibbleMask
+	sets    []nibbleSet
+	chars   []charSet
+	length  int
+	text    bool
+	nibbles []uint16 // EVM: allowed nibbles at every position, for estimates
+	rule
+}
+
+type nibbleSet struct {
+	pos int
+	set uint16
+}
+
+// charSet is a set of ASCII characters allowed at one position of the body.
+type charSet struct {
+	pos int
+	set [2]uint64
+}
+
+func (s *charSet) add(c byte) { s.set[c>>6] |= 1 << (c & 63) }
+
+func (s *charSet) has(c byte) bool { return c < 128 && s.set[c>>6]>>(c&63)&1 == 1 }
+
+func nibbleAt(raw *[20]byte, pos int) byte {
+	b := raw[pos/2]
+	if pos%2 == 0 {
+		return b >> 4
+	}
+	return b & 0x0f
+}
+
+// compileMask turns the positions of a validated template into a maskRule.
+func (m *Matcher) compileMask(pos []config.MaskPos) maskRule {
+	r := maskRule{length: len(pos), text: m.text != nil}
+	if r.text {
+		alpha := m.format.Alphabet()
+		for i, mp := range pos {
+			if mp.Chars == "" {
+				continue
+			}
+			cs := charSet{pos: i}
+			for j := 0; j < len(mp.Chars); j++ {
+				c := mp.Chars[j]
+				if strings.IndexByte(alpha, c) < 0 && (m.caseSensitive || strings.IndexByte(alpha, c^0x20) < 0) {
+					continue // outside the alphabet, from a class range
+				}
+				if !m.caseSensitive && c >= 'A' && c <= 'Z' {
+					c += 'a' - 'A'
+				}
+				cs.add(c)
+			}
+			r.chars = append(r.chars, cs)
+		}
+		return r
+	}
+
+	var val, mask [20]byte
+	r.nibbles = make([]uint16, len(pos))
+	for i, mp := range pos {
+		r.nibbles[i] = 0xffff
+		if mp.Chars == "" {
+			continue
+		}
+		var set uint16
+		letters := false
+		cs := charSet{pos: i}
+		for j := 0; j < len(mp.Chars); j++ {
+			d, ok := hexNibble(mp.Chars[j])
+			if !ok {
+				continue
+			}
+			set |= 1 << d
+			cs.add(mp.Chars[j])
+			letters = letters || d >= 10
+		}
+		r.nibbles[i] = set
+		if bits.OnesCount16(set) == 1 {
+			d := byte(bits.TrailingZeros16(set))
+			if i%2 == 0 {
+				val[i/2] |= d << 4
+				mask[i/2] |= 0xf0
+			} else {
+				val[i/2] |= d
+				mask[i/2] |= 0x0f
+			}
+		} else {
+			r.sets = append(r.sets, nibbleSet{pos: i, set: set})
+		}
+		if m.checksum && letters {
+			r.chars = append(r.chars, cs)
+		}
+	}
+	first, last := 0, len(mask)-1
+	for first <= last && mask[first] == 0 {
+		first++
+	}
+	for last >= first && mask[last] == 0 {
+		last--
+	}
+	r.nib = nibbleMask{off: first, val: val[first : last+1], mask: mask[first : last+1]}
+	return r
+}
+
+func (r *maskRule) match(v *view) bool {
+	if !r.text {
+		raw := (*[20]byte)(v.raw)
+		if !r.nib.match(raw) {
+			return false
+		}
+		for _, s := range r.sets {
+			if s.set>>nibbleAt(raw, s.pos)&1 == 0 {
+				return false
+			}
+		}
+		if len(r.chars) == 0 {
+			return true
+		}
+		return r.matchChars(v.casedBody())
+	}
+	body := v.body()
+	return len(body) == r.length && r.matchChars(body)
+}
+
+func (r *maskRule) matchChars(body []byte) bool {
+	for i := range r.chars {
+		if !r.chars[i].has(body[r.chars[i].pos]) {
+			return false
+		}
+	}
+	return true
+}
+
+// maskProb is the chance that a random body fits r, position by position,
+// and the number of positions that admit only letters in one EIP-55 case
+// each, every one of which halves the chance.
+// For the base58 formats of varying length the length itself is not
+// accounted for.
+func (m *Matcher) maskProb(r *maskRule) (float64, int) {
+	pr := 1.0
+	if r.text {
+		for _, cs := range r.chars {
+			dist := m.text.rest
+			if cs.pos == 0 {
+				dist = m.text.first
+			}
+			p := 0.0
+			for c, q := range dist {
+				if cs.has(c) {
+					p += q
+				}
+			}
+			pr *= p
+		}
+		return pr, 0
+	}
+
+	byPos := make(map[int]*charSet, len(r.chars))
+	for i := range r.chars {
+		byPos[r.chars[i].pos] = &r.chars[i]
+	}
+	caseBits := 0
+	for pos, set := range r.nibbles {
+		cs := byPos[pos]
+		p, pinned := 0.0, cs != nil
+		for d := 0; d < 16; d++ {
+			if set>>d&1 == 0 {
+				continue
+			}
+			if d < 10 || cs == nil {
+				p += 1.0 / 16
+				pinned = false
+				continue
+			}
+			lo, up := cs.has(hexDigits[d]), cs.has(hexDigits[d]-'a'+'A')
+			if lo && up {
+				pinned = false
+				p += 1.0 / 16
+			} else if lo || up {
+				p += 0.5 / 16
+			}
+		}
+		if pinned {
+			caseBits++
+		}
+		pr *= p
+	}
+	return pr, caseBits
+}
@@ -1,80 +1,462 @@
+// Package patterns matches addresses against config.PatternsConfig.
+//
+// Every pattern kind sees the same canonical view of an address: its 40 hex
+// digits without the "0x" prefix, lower-case unless checksum_case is set, in
+// which case the EIP-55 checksum casing is used. Regexps with with_prefix set
+// are the only exception and see "0x" + body, so that they can anchor on 0x.
+// For other address formats the body is the text after the format's lead,
+// e.g. the base58 characters after the "T" of a Tron address.
+//
+// Under checksum_case every rule is first tested on the lower-case view and
+// only its candidates pay for the checksum hash.
 package patterns
 
 import (
+	"WalletTools/internal/crypto"
 	"WalletTools/pkg/config"
+	"fmt"
 	"regexp"
+	"regexp/syntax"
 	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type MatchResult struct {
-	Kind  string // symmetric|specific|edges|regexp
-	Index int
-	Final bool
+	Kind    string // symmetric|placeholder|specific|mask|words|edges|regexp|mnemonic, or a rule's output
+	Index   int
+	Rule    string // name of the rule for rule hits
+	Final   bool
+	MaxHits int // 0 = unlimited
+
+	// Word and Pos are the dictionary word of a words hit and where it
+	// starts in the body, from 0.
+	Word string
+	Pos  int
+
+	id int
 }
 
-func MatchAddress(cfg *config.PatternsConfig, addr string) *MatchResult {
-	check := addr
-	if !cfg.CaseSensitive {
-		check = strings.ToLower(check)
+// Label names the pattern behind a hit: rules[name] for rules, kind[index]
+// for the others.
+func (r *MatchResult) Label() string {
+	if r.Rule != "" {
+		return "rules[" + r.Rule + "]"
 	}
+	return fmt.Sprintf("%s[%d]", r.Kind, r.Index)
+}
 
-	// symmetric
-	if len(cfg.Symmetric) > 0 {
-		for i, p := range cfg.Symmetric {
-			if matchSymmetric(check, p.Prefix, p.Suffix) {
-				return &MatchResult{Kind: "symmetric", Index: i, Final: p.Final}
-			}
+// Matcher is the compiled form of config.PatternsConfig. It is built once per
+// run and is safe for concurrent use by all workers.
+type Matcher struct {
+	format        config.AddressFormat
+	caseSensitive bool           // text formats
+	checksum      bool           // EVM: EIP-55 casing
+	text          *textModel     // nil for EVM
+	net           *crypto.BTCNet // Bitcoin formats only
+
+	// off[id] is set once a pattern has reached its max_hits quota.
+	off    []atomic.Bool
+	active atomic.Int32
+
+	rules       []boolRule
+	symmetric   []placeholderRule
+	placeholder []placeholderRule
+	specific    []specificRule
+	mask        []maskRule
+	words       []wordsRule
+	edges       *edgesRule
+	regexp      []regexpRule
+
+	// phrase is the mnemonic section standing alone, without address
+	// patterns: then every generated phrase is a hit.
+	phrase *rule
+}
+
+// rule is the bookkeeping shared by all compiled patterns.
+type rule struct {
+	id      int
+	final   bool
+	maxHits int
+}
+
+// specificRule keeps the prefix/suffix as nibble masks over the raw address
+// bytes; with checksum_case the body is then compared against the EIP-55
+// casing as well. Text formats (text set) only compare the rendered body.
+type specificRule struct {
+	pre, suf nibbleMask
+	never    bool
+	text     bool
+	strPre   string
+	strSuf   string
+	rule
+}
+
+type edgesRule struct {
+	minCount       int
+	prefix, suffix bool
+	rule
+}
+
+// regexpRule: under checksum_case, fold is a case-insensitive superset of re
+// tried on the lower-case view first.
+type regexpRule struct {
+	re, fold   *regexp.Regexp
+	withPrefix bool
+	rule
+}
+
+// New compiles cfg into a Matcher.
+func New(cfg *config.PatternsConfig) (*Matcher, error) {
+	m := &Matcher{format: cfg.Format, caseSensitive: cfg.CaseSensitive}
+	if m.format == "" {
+		m.format = config.FormatEVM
+	}
+	if m.format == config.FormatEVM {
+		// case_sensitive always meant the checksum casing here.
+		m.checksum = cfg.ChecksumCase || cfg.CaseSensitive
+		m.caseSensitive = false
+	}
+	if m.format != config.FormatEVM {
+		m.text = newTextModel(m.format, m.caseSensitive)
+	}
+	if m.format.Bitcoin() {
+		m.net = crypto.BTCNetwork(m.format.Net())
+	}
+	ids := 0
+	newRule := func(final bool, maxHits int) rule {
+		ids++
+		return rule{id: ids - 1, final: final, maxHits: maxHits}
+	}
+
+	keepCase := m.caseSensitive || m.checksum
+	for _, p := range cfg.Symmetric {
+		r := compilePlaceholder(p.Placeholder(), keepCase)
+		r.rule = newRule(p.Final, p.MaxHits)
+		m.symmetric = append(m.symmetric, r)
+	}
+
+	for i, p := range cfg.Placeholder {
+		if _, _, _, err := p.Parts(); err != nil {
+			return nil, fmt.Errorf("placeholder[%d]: %w", i, err)
 		}
+		r := compilePlaceholder(p, keepCase)
+		r.rule = newRule(p.Final, p.MaxHits)
+		m.placeholder = append(m.placeholder, r)
 	}
 
-	// specific
-	for i, p := range cfg.Specific {
-		pre := p.Prefix
-		suf := p.Suffix
-		if !cfg.CaseSensitive {
-			pre = strings.ToLower(pre)
-			suf = strings.ToLower(suf)
+	for _, p := range cfg.Specific {
+		r := compileSpecific(p, m.format, keepCase)
+		r.rule = newRule(p.Final, p.MaxHits)
+		m.specific = append(m.specific, r)
+	}
+
+	for i, p := range cfg.Mask {
+		pos, err := p.Positions()
+		if err == nil && len(pos) != m.format.BodyLen() {
+			err = fmt.Errorf("template has %d positions, want %d", len(pos), m.format.BodyLen())
 		}
-		if strings.HasPrefix(check, pre) && strings.HasSuffix(check, suf) {
-			return &MatchResult{Kind: "specific", Index: i, Final: p.Final}
+		if err != nil {
+			return nil, fmt.Errorf("mask[%d]: %w", i, err)
 		}
+		r := m.compileMask(pos)
+		r.rule = newRule(p.Final, p.MaxHits)
+		m.mask = append(m.mask, r)
+	}
+
+	for _, p := range cfg.Words {
+		r := compileWords(p, m.checksum)
+		r.rule = newRule(p.Final, p.MaxHits)
+		m.words = append(m.words, r)
 	}
 
-	// edges
 	if cfg.Edges.MinCount > 0 {
-		if cfg.Edges.Side == "prefix" || cfg.Edges.Side == "any" {
-			r := runLenPrefix(check)
-			if r >= cfg.Edges.MinCount {
-				return &MatchResult{Kind: "edges", Index: 0, Final: cfg.Edges.Final}
+		m.edges = newEdgesRule(cfg.Edges)
+		m.edges.rule = newRule(cfg.Edges.Final, cfg.Edges.MaxHits)
+	}
+
+	for i, rp := range cfg.Regexp {
+		r, err := m.compileRegexp(rp)
+		if err != nil {
+			return nil, fmt.Errorf("regexp[%d]: %w", i, err)
+		}
+		r.rule = newRule(rp.Final, rp.MaxHits)
+		m.regexp = append(m.regexp, r)
+	}
+
+	if len(cfg.Rules) > 0 {
+		conds, err := m.compileConditions(cfg.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		for i, rc := range cfg.Rules {
+			r, err := compileBoolRule(rc, conds)
+			if err != nil {
+				return nil, fmt.Errorf("rules[%d]: %w", i, err)
 			}
+			r.rule = newRule(rc.Final, rc.MaxHits)
+			m.rules = append(m.rules, r)
 		}
-		if cfg.Edges.Side == "suffix" || cfg.Edges.Side == "any" {
-			r := runLenSuffix(check)
-			if r >= cfg.Edges.MinCount {
-				return &MatchResult{Kind: "edges", Index: 0, Final: cfg.Edges.Final}
+	}
+
+	if cfg.Mnemonic != nil && !cfg.HasAddressPatterns() {
+		r := newRule(cfg.Mnemonic.Final, cfg.Mnemonic.MaxHits)
+		m.phrase = &r
+	}
+
+	m.off = make([]atomic.Bool, ids)
+	m.active.Store(int32(ids))
+	return m, nil
+}
+
+// Disable turns off the pattern behind r once its quota is used up and
+// returns how many patterns are still active.
+func (m *Matcher) Disable(r *MatchResult) int {
+	if m.off[r.id].CompareAndSwap(false, true) {
+		return int(m.active.Add(-1))
+	}
+	return int(m.active.Load())
+}
+
+func (m *Matcher) result(kind string, index int, r rule) *MatchResult {
+	return &MatchResult{Kind: kind, Index: index, Final: r.final, MaxHits: r.maxHits, id: r.id}
+}
+
+// Format is the address format the matcher was compiled for.
+func (m *Matcher) Format() config.AddressFormat { return m.format }
+
+func newEdgesRule(c config.EdgeConfig) *edgesRule {
+	return &edgesRule{
+		minCount: c.MinCount,
+		prefix:   c.Side == "prefix" || c.Side == "any",
+		suffix:   c.Side == "suffix" || c.Side == "any",
+	}
+}
+
+func (m *Matcher) compileRegexp(rp config.RegexpPattern) (regexpRule, error) {
+	pat := rp.Pattern
+	if !m.caseSensitive && !m.checksum {
+		pat = "(?i)" + pat
+	}
+	re, err := regexp.Compile(pat)
+	if err != nil {
+		return regexpRule{}, err
+	}
+	r := regexpRule{re: re, withPrefix: rp.WithPrefix}
+	if m.checksum {
+		if r.fold, err = foldLower(pat); err != nil {
+			return regexpRule{}, err
+		}
+	}
+	return r, nil
+}
+
+func compileSpecific(p config.SpecificPattern, format config.AddressFormat, keepCase bool) specificRule {
+	r := specificRule{strPre: p.Prefix, strSuf: p.Suffix, text: format != config.FormatEVM}
+	if !keepCase {
+		r.strPre = strings.ToLower(r.strPre)
+		r.strSuf = strings.ToLower(r.strSuf)
+	}
+	if len(p.Prefix) > format.BodyLen() || len(p.Suffix) > format.BodyLen() {
+		r.never = true
+		return r
+	}
+	if r.text {
+		// No nibble masks outside hex: the rendered body is compared.
+		return r
+	}
+
+	var ok bool
+	if r.pre, ok = prefixMask(p.Prefix); !ok {
+		r.never = true
+		return r
+	}
+	if r.suf, ok = suffixMask(p.Suffix); !ok {
+		r.never = true
+	}
+	return r
+}
+
+// foldLower compiles pat so that it also matches the lower-case form of any
+// text pat matches: literals fold case and every upper-case letter of a class
+// brings its lower-case twin. It serves as a cheap filter before pat itself.
+func foldLower(pat string) (*regexp.Regexp, error) {
+	re, err := syntax.Parse(pat, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	var fold func(re *syntax.Regexp)
+	fold = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			re.Flags |= syntax.FoldCase
+		case syntax.OpCharClass:
+			n := len(re.Rune)
+			for i := 0; i < n; i += 2 {
+				lo, hi := max(re.Rune[i], 'A'), min(re.Rune[i+1], 'Z')
+				if lo <= hi {
+					re.Rune = append(re.Rune, lo+'a'-'A', hi+'a'-'A')
+				}
 			}
 		}
+		for _, sub := range re.Sub {
+			fold(sub)
+		}
 	}
+	fold(re)
+	return regexp.Compile(re.String())
+}
 
-	// regexp
-	for i, rp := range cfg.Regexp {
-		pat := rp.Pattern
-		if !cfg.CaseSensitive {
-			pat = "(?i)" + pat
+// MatchPhrase is the hit of any generated phrase when the mnemonic section is
+// the only pattern, and nil otherwise: the phrase generator enforces the
+// section itself.
+func (m *Matcher) MatchPhrase() *MatchResult {
+	if p := m.phrase; p != nil && !m.off[p.id].Load() {
+		return m.result("mnemonic", 0, *p)
+	}
+	return nil
+}
+
+// MatchAddress matches a 0x-prefixed hex address.
+func (m *Matcher) MatchAddress(addr string) *MatchResult {
+	raw := common.HexToAddress(addr)
+	return m.MatchRaw((*[20]byte)(&raw))
+}
+
+// MatchRaw matches a raw 20-byte address, or key hash for Bitcoin formats.
+// The text form is only rendered (on the stack) for rules that need it.
+func (m *Matcher) MatchRaw(addr *[20]byte) *MatchResult {
+	return m.match(addr[:])
+}
+
+// MatchPayload matches what an address of the matcher's format encodes, of
+// its PayloadLen: MatchRaw for 20 bytes, the 32-byte output key for P2TR.
+func (m *Matcher) MatchPayload(p []byte) *MatchResult {
+	return m.match(p)
+}
+
+func (m *Matcher) match(raw []byte) *MatchResult {
+	v := m.view(raw)
+
+	for i := range m.rules {
+		p := &m.rules[i]
+		if !m.off[p.id].Load() && p.expr.eval(&v) {
+			r := m.result(p.kind, i, p.rule)
+			r.Rule = p.name
+			for _, w := range p.words {
+				if wi, pos, ok := w.match(&v); ok {
+					r.Word, r.Pos = w.words[wi], pos
+					break
+				}
+			}
+			return r
 		}
-		re, err := regexp.Compile(pat)
-		if err != nil {
+	}
+
+	for i := range m.symmetric {
+		p := &m.symmetric[i]
+		if !m.off[p.id].Load() && p.test(&v) {
+			return m.result("symmetric", i, p.rule)
+		}
+	}
+
+	for i := range m.placeholder {
+		p := &m.placeholder[i]
+		if !m.off[p.id].Load() && p.test(&v) {
+			return m.result("placeholder", i, p.rule)
+		}
+	}
+
+	for i := range m.specific {
+		p := &m.specific[i]
+		if !m.off[p.id].Load() && p.test(&v) {
+			return m.result("specific", i, p.rule)
+		}
+	}
+
+	for i := range m.mask {
+		p := &m.mask[i]
+		if !m.off[p.id].Load() && p.match(&v) {
+			return m.result("mask", i, p.rule)
+		}
+	}
+
+	for i := range m.words {
+		p := &m.words[i]
+		if m.off[p.id].Load() {
 			continue
 		}
-		if re.MatchString(check) {
-			return &MatchResult{Kind: "regexp", Index: i, Final: rp.Final}
+		if w, pos, ok := p.match(&v); ok {
+			r := m.result("words", i, p.rule)
+			r.Word, r.Pos = p.words[w], pos
+			return r
+		}
+	}
+
+	if e := m.edges; e != nil && !m.off[e.id].Load() && e.test(&v) {
+		return m.result("edges", 0, e.rule)
+	}
+
+	for i := range m.regexp {
+		p := &m.regexp[i]
+		if !m.off[p.id].Load() && p.test(&v) {
+			return m.result("regexp", i, p.rule)
 		}
 	}
 	return nil
 }
 
-func runLenPrefix(s string) int {
-	if s == "" {
+func (m *Matcher) view(raw []byte) view {
+	return view{raw: raw, format: m.format, net: m.net, caseSensitive: m.caseSensitive, checksum: m.checksum}
+}
+
+// test matches the rule on v: the nibble masks of the raw address first for
+// EVM, then the rendered or cased body where that is needed.
+func (p *specificRule) test(v *view) bool {
+	if p.never {
+		return false
+	}
+	if !p.text && (!p.pre.match((*[20]byte)(v.raw)) || !p.suf.match((*[20]byte)(v.raw))) {
+		return false
+	}
+	if v.checksum || p.text {
+		body := v.casedBody()
+		return hasPrefix(body, p.strPre) && hasSuffix(body, p.strSuf)
+	}
+	return true
+}
+
+func (p *placeholderRule) test(v *view) bool {
+	return p.match(v.body()) && (!v.checksum || p.match(v.casedBody()))
+}
+
+func (e *edgesRule) test(v *view) bool {
+	return e.match(v.body()) && (!v.checksum || e.match(v.casedBody()))
+}
+
+func (p *regexpRule) test(v *view) bool {
+	if p.fold != nil && !p.fold.Match(v.text(p.withPrefix, false)) {
+		return false
+	}
+	return p.re.Match(v.text(p.withPrefix, true))
+}
+
+func (e *edgesRule) match(body []byte) bool {
+	return e.prefix && runLenPrefix(body) >= e.minCount ||
+		e.suffix && runLenSuffix(body) >= e.minCount
+}
+
+func hasPrefix(b []byte, s string) bool {
+	return len(b) >= len(s) && string(b[:len(s)]) == s
+}
+
+func hasSuffix(b []byte, s string) bool {
+	return len(b) >= len(s) && string(b[len(b)-len(s):]) == s
+}
+
+func runLenPrefix(s []byte) int {
+	if len(s) == 0 {
 		return 0
 	}
 	first := s[0]
@@ -89,8 +471,8 @@ func runLenPrefix(s string) int {
 	return n
 }
 
-func runLenSuffix(s string) int {
-	if s == "" {
+func runLenSuffix(s []byte) int {
+	if len(s) == 0 {
 		return 0
 	}
 	last := s[len(s)-1]
@@ -104,47 +486,3 @@ func runLenSuffix(s string) int {
 	}
 	return n
 }
-
-func matchSymmetric(addr, pre, suf string) bool {
-	if len(addr) < len(pre)+len(suf) {
-		return false
-	}
-
-	prefixPart := addr[:len(pre)]
-	suffixPart := addr[len(addr)-len(suf):]
-
-	checkPattern := func(pattern, part string) (byte, bool) {
-		if len(pattern) != len(part) {
-			return 0, false
-		}
-		var symbol byte
-		for i := 0; i < len(pattern); i++ {
-			switch pattern[i] {
-			case 'X', 'Y':
-				if symbol == 0 {
-					symbol = part[i]
-				} else if part[i] != symbol {
-					return 0, false
-				}
-			default:
-				// Любой другой символ запрещён
-				return 0, false
-			}
-		}
-		return symbol, true
-	}
-
-	symPre, okPre := checkPattern(pre, prefixPart)
-	symSuf, okSuf := checkPattern(suf, suffixPart)
-	if !okPre || !okSuf {
-		return false
-	}
-
-	if strings.Contains(pre, "X") && strings.Contains(suf, "X") {
-		return symPre == symSuf
-	}
-	if strings.Contains(pre, "Y") && strings.Contains(suf, "Y") {
-		return symPre == symSuf
-	}
-	return true
-}
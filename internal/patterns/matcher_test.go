@@ -0,0 +1,429 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+
+	"WalletTools/pkg/config"
+)
+
+// matchCase is one address against a config: want is the kind of the hit,
+// "" for none.
+type matchCase struct {
+	addr string
+	want string
+}
+
+func runMatches(t *testing.T, cfg config.PatternsConfig, cases []matchCase) {
+	t.Helper()
+	if cfg.Format == "" {
+		cfg.Format = config.FormatEVM
+	}
+	m, err := New(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		got := ""
+		if r := m.MatchAddress(c.addr); r != nil {
+			got = r.Kind
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want %q", c.addr, got, c.want)
+		}
+	}
+}
+
+func TestSpecific(t *testing.T) {
+	runMatches(t, config.PatternsConfig{
+		Specific: []config.SpecificPattern{{Prefix: "abc", Suffix: "9d"}},
+	}, []matchCase{
+		{"0xabc000000000000000000000000000000000009d", "specific"},
+		{"0xABC000000000000000000000000000000000009D", "specific"},
+		{"0xabd000000000000000000000000000000000009d", ""},
+		{"0xabc00000000000000000000000000000000000d9", ""},
+		{"0x0abc00000000000000000000000000000000009d", ""}, // the body, not "0x" + body
+	})
+
+	// Odd offsets put the suffix into the low nibbles.
+	runMatches(t, config.PatternsConfig{
+		Specific: []config.SpecificPattern{{Suffix: "00f"}},
+	}, []matchCase{
+		{"0x123456789012345678901234567890123456700f", "specific"},
+		{"0x123456789012345678901234567890123456710f", ""},
+		{"0x123456789012345678901234567890123456700e", ""},
+	})
+}
+
+func TestSymmetric(t *testing.T) {
+	runMatches(t, config.PatternsConfig{
+		Symmetric: []config.SymmetricPattern{{Prefix: "XX", Suffix: "YY"}},
+	}, []matchCase{
+		{"0x7712345678901234567890123456789012345688", "symmetric"},
+		{"0x7712345678901234567890123456789012345677", "symmetric"},
+		{"0x7812345678901234567890123456789012345688", ""},
+		{"0x7712345678901234567890123456789012345687", ""},
+	})
+}
+
+func TestEdges(t *testing.T) {
+	runMatches(t, config.PatternsConfig{
+		Edges: config.EdgeConfig{MinCount: 4, Side: "prefix"},
+	}, []matchCase{
+		{"0x0000123456789012345678901234567890123456", "edges"},
+		{"0x0001234567890123456789012345678901233333", ""},
+	})
+	runMatches(t, config.PatternsConfig{
+		Edges: config.EdgeConfig{MinCount: 4, Side: "any"},
+	}, []matchCase{
+		{"0x0001234567890123456789012345678901233333", "edges"},
+		{"0x0001234567890123456789012345678901234333", ""},
+	})
+}
+
+func TestRegexp(t *testing.T) {
+	runMatches(t, config.PatternsConfig{
+		Regexp: []config.RegexpPattern{{Pattern: "^dead"}},
+	}, []matchCase{
+		{"0xDEAD567890123456789012345678901234567890", "regexp"},
+		{"0x0dead67890123456789012345678901234567890", ""},
+	})
+	runMatches(t, config.PatternsConfig{
+		Regexp: []config.RegexpPattern{{Pattern: "^0xdead", WithPrefix: true}},
+	}, []matchCase{
+		{"0xdead567890123456789012345678901234567890", "regexp"},
+		{"0x1dead67890123456789012345678901234567890", ""},
+	})
+}
+
+func TestTextFormat(t *testing.T) {
+	// The zero address is T9yD14Nj9j7xAB4dbGeiX9h8unkKHxuWwb on Tron; the
+	// body follows the "T".
+	runMatches(t, config.PatternsConfig{
+		Format:   config.FormatTron,
+		Specific: []config.SpecificPattern{{Prefix: "9yd", Suffix: "wwb"}},
+	}, []matchCase{
+		{"0x0000000000000000000000000000000000000000", "specific"},
+		{"0x0000000000000000000000000000000000000001", ""},
+	})
+}
+
+// Addresses of the EIP-55 test vectors in their checksum casing:
+// 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed and
+// 0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359.
+func TestChecksumCase(t *testing.T) {
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Specific:     []config.SpecificPattern{{Prefix: "5aAe", Suffix: "BeAed"}},
+	}, []matchCase{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "specific"},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", "specific"},
+	})
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Specific:     []config.SpecificPattern{{Prefix: "5aae"}},
+	}, []matchCase{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", ""},
+	})
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Specific:     []config.SpecificPattern{{Suffix: "d359"}},
+	}, []matchCase{
+		{"0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "specific"},
+	})
+
+	// Regexps are tried on the lower-case view first: classes bring their
+	// lower-case twins, then the cased body decides.
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Regexp:       []config.RegexpPattern{{Pattern: "^5a[A-F]e[a-f]"}},
+	}, []matchCase{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "regexp"},
+		{"0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", ""},
+	})
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Regexp:       []config.RegexpPattern{{Pattern: "^5a[a-f]"}},
+	}, []matchCase{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", ""},
+	})
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Regexp:       []config.RegexpPattern{{Pattern: "^0xfB69", WithPrefix: true}},
+	}, []matchCase{
+		{"0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "regexp"},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", ""},
+	})
+}
+
+func TestFoldLower(t *testing.T) {
+	tests := []struct {
+		pat, in string
+		want    bool
+	}{
+		{"^AB", "ab", true},
+		{"^[A-C]x", "bx", true},
+		{"^[A-C]x", "dx", false},
+		{"^[0-9A]+$", "09a", true},
+		{"^(?:Be|ef)$", "be", true},
+	}
+	for _, tt := range tests {
+		re, err := foldLower(tt.pat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := re.MatchString(tt.in); got != tt.want {
+			t.Errorf("%s on %q: got %v, want %v", tt.pat, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	runMatches(t, config.PatternsConfig{
+		Mask: []config.MaskPattern{{Template: "de[a-c]d??????????????????????????????[0-3]?beef"}},
+	}, []matchCase{
+		{"0xdead1234567890123456789012345678903abeef", "mask"},
+		{"0xDEBD1234567890123456789012345678900fBEEF", "mask"},
+		{"0xdedd1234567890123456789012345678903abeef", ""},
+		{"0xdead1234567890123456789012345678904abeef", ""},
+		{"0xdead1234567890123456789012345678903abeee", ""},
+	})
+
+	// Under checksum_case a letter pins its case; classes may allow both.
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Mask:         []config.MaskPattern{{Template: "5aA[eE]?????????????????????????????????Ae[a-f]"}},
+	}, []matchCase{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "mask"},
+	})
+	runMatches(t, config.PatternsConfig{
+		ChecksumCase: true,
+		Mask:         []config.MaskPattern{{Template: "5aa?????????????????????????????????????"}},
+	}, []matchCase{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", ""},
+	})
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		side     string
+		words    []string
+		checksum bool
+		addr     string
+		word     string // "" for no hit
+		pos      int
+	}{
+		// "bee" is a prefix of "beef": the longest word wins.
+		{"prefix", []string{"bee", "beef"}, false, "0xbeef567890123456789012345678901234567890", "beef", 0},
+		{"prefix", []string{"beef", "bee"}, false, "0xbee0567890123456789012345678901234567890", "bee", 0},
+		{"prefix", []string{"bee", "beef"}, false, "0x0beef67890123456789012345678901234567890", "", 0},
+		// "cafe" ends with "afe", "caf" is a prefix of "cafe".
+		{"suffix", []string{"afe", "caf", "cafe"}, false, "0x123456789012345678901234567890123456cafe", "cafe", 36},
+		{"suffix", []string{"afe", "caf", "cafe"}, false, "0x12345678901234567890123456789012345670fe", "", 0},
+		{"suffix", []string{"afe", "caf", "cafe"}, false, "0x1234567890123456789012345678901234560afe", "afe", 37},
+		{"suffix", []string{"afe", "caf", "cafe"}, false, "0x12345678901234567890123456789012345caf00", "", 0},
+		{"any", []string{"dead", "deadbeef", "beef"}, false, "0x1234deadbeef5678901234567890123456789012", "deadbeef", 4},
+		{"any", []string{"dead", "beef", "c0ffee"}, false, "0x12beef345678dead9012345678901234567890ab", "beef", 2},
+		{"any", []string{"dead"}, false, "0x1234567890123456789012345678901234567890", "", 0},
+		// Under checksum_case a word keeps its case.
+		{"suffix", []string{"BeAed"}, true, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "BeAed", 35},
+		{"suffix", []string{"beaed"}, true, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "", 0},
+		{"any", []string{"c9b9", "C9b9"}, true, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "C9b9", 14},
+	}
+	for _, tt := range tests {
+		m, err := New(&config.PatternsConfig{
+			Format:       config.FormatEVM,
+			ChecksumCase: tt.checksum,
+			Words:        []config.WordsPattern{{Side: tt.side, Words: tt.words}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := m.MatchAddress(tt.addr)
+		switch {
+		case r == nil && tt.word != "":
+			t.Errorf("%s %v on %s: no hit, want %s", tt.side, tt.words, tt.addr, tt.word)
+		case r != nil && tt.word == "":
+			t.Errorf("%s %v on %s: got %s, want no hit", tt.side, tt.words, tt.addr, r.Word)
+		case r != nil && (r.Word != tt.word || r.Pos != tt.pos):
+			t.Errorf("%s %v on %s: got %s at %d, want %s at %d", tt.side, tt.words, tt.addr, r.Word, r.Pos, tt.word, tt.pos)
+		}
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		p    config.PlaceholderPattern
+		hits []string
+		miss []string
+	}{
+		{config.PlaceholderPattern{Pattern: "ABBA...ABBA"}, []string{
+			"0x1221567890123456789012345678901234561221",
+			"0x7777567890123456789012345678901234567777", // A and B may coincide
+		}, []string{
+			"0x1221567890123456789012345678901234561231",
+			"0x1221567890123456789012345678901234562112",
+		}},
+		{config.PlaceholderPattern{Pattern: "ABBA...ABBA", Distinct: true}, []string{
+			"0x1221567890123456789012345678901234561221",
+		}, []string{
+			"0x7777567890123456789012345678901234567777",
+		}},
+		// X repeats, Z and X must differ, Y is free.
+		{config.PlaceholderPattern{Pattern: "XYX?Z...0X", Differ: []string{"XZ"}}, []string{
+			"0xa1a2b67890123456789012345678901234560a",
+			"0xaaa2b67890123456789012345678901234560a",
+		}, []string{
+			"0xa1a2a67890123456789012345678901234560a",
+			"0xa1b2c67890123456789012345678901234560a",
+			"0xa1a2b67890123456789012345678901234561a",
+		}},
+		// Without "...", the pattern is the whole body: a palindrome.
+		{config.PlaceholderPattern{Pattern: "ABCDEFGHIJKLMNOPQRSTTSRQPONMLKJIHGFEDCBA"}, []string{
+			"0x0123456789abcdef01233210fedcba9876543210",
+		}, []string{
+			"0x0123456789abcdef01233210fedcba9876543211",
+		}},
+	}
+	for _, tt := range tests {
+		var cases []matchCase
+		for _, a := range tt.hits {
+			cases = append(cases, matchCase{pad(a), "placeholder"})
+		}
+		for _, a := range tt.miss {
+			cases = append(cases, matchCase{pad(a), ""})
+		}
+		runMatches(t, config.PatternsConfig{Placeholder: []config.PlaceholderPattern{tt.p}}, cases)
+	}
+}
+
+// pad widens a short test address to 40 digits in its middle, keeping the
+// ends that the patterns pin.
+func pad(a string) string {
+	const n = 42
+	if len(a) >= n {
+		return a
+	}
+	mid := len(a) / 2
+	return a[:mid] + "5555555555555555555555555555555555555555"[:n-len(a)] + a[mid:]
+}
+
+// legacySymmetric is the symmetric matcher from before placeholders: each
+// side is a run of one character, the same on both sides when they share X
+// or Y.
+func legacySymmetric(body, pre, suf string) bool {
+	if len(body) < len(pre)+len(suf) {
+		return false
+	}
+	run := func(s string) (byte, bool) {
+		for i := 1; i < len(s); i++ {
+			if s[i] != s[0] {
+				return 0, false
+			}
+		}
+		return s[0], true
+	}
+	a, okA := run(body[:len(pre)])
+	b, okB := run(body[len(body)-len(suf):])
+	if !okA || !okB {
+		return false
+	}
+	if strings.Contains(pre, "X") && strings.Contains(suf, "X") || strings.Contains(pre, "Y") && strings.Contains(suf, "Y") {
+		return a == b
+	}
+	return true
+}
+
+// Symmetric patterns compile to placeholders and keep the X/Y sharing.
+func TestSymmetricAsPlaceholder(t *testing.T) {
+	sides := [][2]string{
+		{"XX", "YY"}, {"XXXX", "XXXX"}, {"XY", "YX"}, {"XX", "Y"}, {"YYY", "XY"}, {"X", "X"},
+	}
+	addrs := []string{
+		"0x7712345678901234567890123456789012345688",
+		"0x7777345678901234567890123456789012347777",
+		"0x7777345678901234567890123456789012348888",
+		"0x7812345678901234567890123456789012345677",
+		"0x7772345678901234567890123456789012345677",
+		"0x7772345678901234567890123456789012345777",
+		"0xaaa2345678901234567890123456789012345aaa",
+	}
+	for _, s := range sides {
+		sp := config.SymmetricPattern{Prefix: s[0], Suffix: s[1]}
+		m, err := New(&config.PatternsConfig{Format: config.FormatEVM, Symmetric: []config.SymmetricPattern{sp}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range addrs {
+			want := legacySymmetric(a[2:], s[0], s[1])
+			if got := m.MatchAddress(a) != nil; got != want {
+				t.Errorf("%s/%s (%s) on %s: got %v, want %v", s[0], s[1], sp.Placeholder().Pattern, a, got, want)
+			}
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	conds := map[string]config.Condition{
+		"zeros": {Specific: &config.SpecificPattern{Prefix: "0000"}},
+		"beef":  {Specific: &config.SpecificPattern{Suffix: "beef"}},
+		"cafe":  {Specific: &config.SpecificPattern{Suffix: "cafe"}},
+		"dead":  {Words: &config.WordsPattern{Side: "any", Words: []string{"dead"}}},
+	}
+	const (
+		zerosBeef = "0x000056789012345678901234567890123456beef"
+		zerosCafe = "0x000056789012345678901234567890123456cafe"
+		beefOnly  = "0x123456789012345678901234567890123456beef"
+		zerosOnly = "0x0000567890123456789012345678901234567890"
+		deadCafe  = "0x1234dead9012345678901234567890123456cafe"
+	)
+	tests := []struct {
+		when string
+		hits []string
+		miss []string
+	}{
+		// AND binds tighter than OR: zeros AND beef, or cafe.
+		{"zeros AND beef OR cafe", []string{zerosBeef, zerosCafe, deadCafe}, []string{beefOnly, zerosOnly}},
+		{"zeros AND (beef OR cafe)", []string{zerosBeef, zerosCafe}, []string{deadCafe, beefOnly, zerosOnly}},
+		// NOT binds tighter than AND: (NOT zeros) AND beef.
+		{"NOT zeros AND beef", []string{beefOnly}, []string{zerosBeef, zerosOnly}},
+		{"NOT (zeros AND beef)", []string{beefOnly, zerosOnly, deadCafe}, []string{zerosBeef}},
+		{"zeros OR NOT beef AND NOT cafe", []string{zerosBeef, zerosOnly}, []string{beefOnly, deadCafe}},
+	}
+	for _, tt := range tests {
+		cfg := config.PatternsConfig{
+			Conditions: conds,
+			Rules:      []config.Rule{{Name: "r", When: tt.when, Output: "out"}},
+		}
+		var cases []matchCase
+		for _, a := range tt.hits {
+			cases = append(cases, matchCase{a, "out"})
+		}
+		for _, a := range tt.miss {
+			cases = append(cases, matchCase{a, ""})
+		}
+		runMatches(t, cfg, cases)
+	}
+
+	// Rules come before the other patterns and report the word of a words
+	// condition outside NOT.
+	m, err := New(&config.PatternsConfig{
+		Format:     config.FormatEVM,
+		Specific:   []config.SpecificPattern{{Suffix: "cafe"}},
+		Conditions: conds,
+		Rules: []config.Rule{
+			{Name: "neg", When: "NOT dead AND cafe"},
+			{Name: "pos", When: "dead AND cafe"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := m.MatchAddress(deadCafe)
+	if r == nil || r.Kind != "pos" || r.Rule != "pos" || r.Word != "dead" || r.Pos != 4 || r.Label() != "rules[pos]" {
+		t.Errorf("%s: got %+v, want rule pos with word dead at 4", deadCafe, r)
+	}
+	if r := m.MatchAddress(zerosCafe); r == nil || r.Kind != "neg" || r.Word != "" {
+		t.Errorf("%s: got %+v, want rule neg without word", zerosCafe, r)
+	}
+}
@@ -0,0 +1,207 @@
+package patterns
+
+import (
+	"WalletTools/internal/crypto"
+	"WalletTools/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// nibbleMask pins a run of hex digits to fixed positions of the raw address:
+// byte i of the address must satisfy addr[off+i]&mask[i] == val[i].
+type nibbleMask struct {
+	off  int
+	val  []byte
+	mask []byte
+}
+
+func (n nibbleMask) match(addr *[20]byte) bool {
+	for i, v := range n.val {
+		if addr[n.off+i]&n.mask[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// nibblesMask builds a mask for the hex digits s starting at nibble position
+// pos (0..39) of the address.
+func nibblesMask(s string, pos int) (nibbleMask, bool) {
+	if s == "" {
+		return nibbleMask{}, true
+	}
+	first, last := pos/2, (pos+len(s)-1)/2
+	m := nibbleMask{
+		off:  first,
+		val:  make([]byte, last-first+1),
+		mask: make([]byte, last-first+1),
+	}
+	for i := 0; i < len(s); i++ {
+		d, ok := hexNibble(s[i])
+		if !ok {
+			return nibbleMask{}, false
+		}
+		p := pos + i
+		b := p/2 - first
+		if p%2 == 0 {
+			m.val[b] |= d << 4
+			m.mask[b] |= 0xf0
+		} else {
+			m.val[b] |= d
+			m.mask[b] |= 0x0f
+		}
+	}
+	return m, true
+}
+
+func prefixMask(s string) (nibbleMask, bool) { return nibblesMask(s, 0) }
+
+func suffixMask(s string) (nibbleMask, bool) { return nibblesMask(s, 40-len(s)) }
+
+// view renders the address into a stack buffer in its format, once and only
+// when a rule asks for it: "0x" + body in lower case for EVM; the base58 or
+// bech32 address, lower-cased unless caseSensitive, otherwise. With checksum
+// the EIP-55 casing of an EVM address is rendered separately, as late as
+// possible: it costs a keccak hash.
+type view struct {
+	raw           []byte
+	format        config.AddressFormat
+	net           *crypto.BTCNet
+	caseSensitive bool
+	checksum      bool
+	buf           [90]byte // longest bech32 string
+	n             int      // rendered length, 0 until rendered
+	cased         [42]byte
+	hasCased      bool
+}
+
+// body is the canonical view: the address without its lead, e.g. the 40 hex
+// digits without "0x".
+func (v *view) body() []byte {
+	return v.full()[v.format.LeadLen():]
+}
+
+func (v *view) full() []byte {
+	if v.n > 0 {
+		return v.buf[:v.n]
+	}
+	if v.format != config.FormatEVM {
+		v.n = len(appendAddress(v.buf[:0], v.format, v.net, v.raw))
+		if !v.caseSensitive {
+			lower(v.buf[v.format.LeadLen():v.n])
+		}
+		return v.buf[:v.n]
+	}
+	v.buf[0], v.buf[1] = '0', 'x'
+	for i, b := range v.raw {
+		v.buf[2+2*i] = hexDigits[b>>4]
+		v.buf[3+2*i] = hexDigits[b&0x0f]
+	}
+	v.n = 42
+	return v.buf[:v.n]
+}
+
+// casedBody and casedFull are body and full in the case the patterns are
+// compared against in the end: the EIP-55 casing under checksum, the plain
+// view otherwise. A cased match implies a match of the plain view, which is
+// therefore tested first.
+func (v *view) casedBody() []byte {
+	return v.casedFull()[v.format.LeadLen():]
+}
+
+func (v *view) casedFull() []byte {
+	if !v.checksum {
+		return v.full()
+	}
+	if !v.hasCased {
+		copy(v.cased[:], v.full())
+		eip55(v.cased[2:])
+		v.hasCased = true
+	}
+	return v.cased[:]
+}
+
+// text is what a regexp sees: the body, or the full address with
+// withPrefix, in the cased or the plain view.
+func (v *view) text(withPrefix, cased bool) []byte {
+	switch {
+	case withPrefix && cased:
+		return v.casedFull()
+	case withPrefix:
+		return v.full()
+	case cased:
+		return v.casedBody()
+	}
+	return v.body()
+}
+
+// appendAddress appends the address of payload raw in a text format.
+func appendAddress(dst []byte, f config.AddressFormat, net *crypto.BTCNet, raw []byte) []byte {
+	switch f.Script() {
+	case "tron":
+		return crypto.AppendTronAddress(dst, (*[20]byte)(raw))
+	case "p2pkh":
+		return crypto.AppendP2PKH(dst, net, (*[20]byte)(raw))
+	case "p2wpkh":
+		return crypto.AppendP2WPKH(dst, net, (*[20]byte)(raw))
+	case "p2tr":
+		return crypto.AppendP2TR(dst, net, (*[32]byte)(raw))
+	case "solana":
+		return crypto.AppendSolanaAddress(dst, (*[32]byte)(raw))
+	case "cosmos":
+		return crypto.AppendCosmosAddress(dst, f.HRP(), (*[20]byte)(raw))
+	}
+	return dst
+}
+
+// Render is the address of payload raw in format f as wallets show it:
+// EIP-55 cased for EVM.
+func Render(f config.AddressFormat, raw []byte) string {
+	if f == config.FormatEVM || f == "" {
+		return common.BytesToAddress(raw).Hex()
+	}
+	var buf [90]byte
+	return string(appendAddress(buf[:0], f, crypto.BTCNetwork(f.Net()), raw))
+}
+
+func lower(b []byte) {
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+}
+
+// eip55 upper-cases the letters of a lower-case hex body whose matching
+// keccak nibble is >= 8.
+func eip55(body []byte) {
+	h := gethcrypto.Keccak256(body)
+	for i := range body {
+		c := body[i]
+		if c < 'a' {
+			continue
+		}
+		hn := h[i/2]
+		if i%2 == 0 {
+			hn >>= 4
+		}
+		if hn&0x0f >= 8 {
+			body[i] = c - 'a' + 'A'
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package patterns
+
+import (
+	"maps"
+	"sort"
+	"strings"
+
+	"WalletTools/pkg/config"
+)
+
+// placeholderRule is a compiled placeholder pattern, and the form symmetric
+// patterns are matched in. Its slots are ordered to fail early: literals
+// first, then the variables that repeat, each one's occurrences together,
+// and the variables bound only once last.
+type placeholderRule struct {
+	slots  []slot
+	minLen int
+	vars   int
+	differ [][2]uint8 // pairs of variables that must bind different characters
+	rule
+}
+
+// slot is one pinned position: off counts from the start of the body, or
+// from its end when negative. v is the variable, c the literal when v < 0.
+type slot struct {
+	off int
+	v   int8
+	c   byte
+}
+
+func compilePlaceholder(p config.PlaceholderPattern, keepCase bool) placeholderRule {
+	pre, suf, _, _ := p.Parts()
+	r := placeholderRule{minLen: len(pre) + len(suf)}
+
+	index := map[byte]int8{}
+	count := map[int8]int{}
+	add := func(off int, s config.PlaceholderSlot) {
+		switch {
+		case s.Var != 0:
+			v, ok := index[s.Var]
+			if !ok {
+				v = int8(len(index))
+				index[s.Var] = v
+			}
+			count[v]++
+			r.slots = append(r.slots, slot{off: off, v: v})
+		case s.Char != 0:
+			c := s.Char
+			if !keepCase && c >= 'A' && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			r.slots = append(r.slots, slot{off: off, v: -1, c: c})
+		}
+	}
+	for i, s := range pre {
+		add(i, s)
+	}
+	for i, s := range suf {
+		add(i-len(suf), s)
+	}
+	r.vars = len(index)
+
+	rank := func(s slot) int {
+		switch {
+		case s.v < 0:
+			return -1
+		case count[s.v] > 1:
+			return int(s.v)
+		}
+		return r.vars + int(s.v)
+	}
+	sort.SliceStable(r.slots, func(i, j int) bool { return rank(r.slots[i]) < rank(r.slots[j]) })
+
+	var groups []string
+	if p.Distinct {
+		var all strings.Builder
+		for c := range index {
+			all.WriteByte(c)
+		}
+		groups = append(groups, all.String())
+	}
+	groups = append(groups, p.Differ...)
+	seen := map[[2]uint8]bool{}
+	for _, g := range groups {
+		for i := 0; i < len(g); i++ {
+			for j := i + 1; j < len(g); j++ {
+				a, b := uint8(index[g[i]]), uint8(index[g[j]])
+				if a > b {
+					a, b = b, a
+				}
+				if pair := [2]uint8{a, b}; a != b && !seen[pair] {
+					seen[pair] = true
+					r.differ = append(r.differ, pair)
+				}
+			}
+		}
+	}
+	return r
+}
+
+func (r *placeholderRule) match(body []byte) bool {
+	if len(body) < r.minLen {
+		return false
+	}
+	var bound [26]byte
+	for _, s := range r.slots {
+		i := s.off
+		if i < 0 {
+			i += len(body)
+		}
+		c := body[i]
+		switch {
+		case s.v < 0:
+			if c != s.c {
+				return false
+			}
+		case bound[s.v] == 0:
+			bound[s.v] = c
+		case bound[s.v] != c:
+			return false
+		}
+	}
+	for _, d := range r.differ {
+		if bound[d[0]] == bound[d[1]] {
+			return false
+		}
+	}
+	return true
+}
+
+// placeholderProb is the chance of r on a random body. Each variable takes
+// the weight of all the characters it could bind at its positions; a
+// variable that must differ from earlier ones then loses the chance of
+// coinciding with any of them, which is exact for uniform characters.
+// Under checksum_case variables differ as digits, whatever their case.
+func (m *Matcher) placeholderProb(r *placeholderRule) float64 {
+	first, rest := m.charDist(true), m.charDist(false)
+	dist := func(s slot) map[byte]float64 {
+		if s.off == 0 {
+			return first
+		}
+		return rest
+	}
+
+	pr := 1.0
+	weights := make([]map[byte]float64, r.vars)
+	for _, s := range r.slots {
+		d := dist(s)
+		if s.v < 0 {
+			pr *= d[s.c]
+			continue
+		}
+		w := weights[s.v]
+		if w == nil {
+			weights[s.v] = maps.Clone(d)
+			continue
+		}
+		for c := range w {
+			w[c] *= d[c]
+		}
+	}
+	total := make([]float64, r.vars)
+	for v, w := range weights {
+		for _, q := range w {
+			total[v] += q
+		}
+		pr *= total[v]
+	}
+
+	clash := make([]float64, r.vars)
+	for _, d := range r.differ {
+		a, b := d[0], d[1]
+		if total[a] == 0 || total[b] == 0 {
+			return 0
+		}
+		clash[b] += m.coincide(weights[a], weights[b]) / (total[a] * total[b])
+	}
+	for _, q := range clash {
+		pr *= max(1-q, 0)
+	}
+	return pr
+}
+
+// coincide is the weight of two variables binding the same character;
+// under checksum_case the same digit in either case.
+func (m *Matcher) coincide(a, b map[byte]float64) float64 {
+	if !m.checksum {
+		same := 0.0
+		for c, q := range a {
+			same += q * b[c]
+		}
+		return same
+	}
+	var fa, fb [256]float64
+	for c, q := range a {
+		fa[c|0x20] += q
+	}
+	for c, q := range b {
+		fb[c|0x20] += q
+	}
+	same := 0.0
+	for c := range fa {
+		same += fa[c] * fb[c]
+	}
+	return same
+}
+
+// charDist is the chance of each character at the first or any other
+// position of the body, in the view the patterns are compared against.
+func (m *Matcher) charDist(first bool) map[byte]float64 {
+	if m.text != nil {
+		if first {
+			return m.text.first
+		}
+		return m.text.rest
+	}
+	d := make(map[byte]float64, 22)
+	for i := 0; i < 16; i++ {
+		c := hexDigits[i]
+		switch {
+		case !m.checksum || i < 10:
+			d[c] = 1.0 / 16
+		default:
+			d[c], d[c-'a'+'A'] = 1.0/32, 1.0/32
+		}
+	}
+	return d
+}
@@ -0,0 +1,146 @@
+package patterns
+
+import (
+	"fmt"
+	"sort"
+
+	"WalletTools/pkg/config"
+)
+
+// boolRule is a compiled rule: an expression over conditions, each of them
+// one compiled pattern. words are its dictionaries outside any NOT, which
+// give a hit its word.
+type boolRule struct {
+	name, kind string
+	expr       *cond
+	words      []*wordsRule
+	rule
+}
+
+// cond is a node of a rule expression: AND, OR and NOT of args, or the test
+// of a condition when op is empty.
+type cond struct {
+	op   string
+	args []*cond
+	test func(v *view) bool
+}
+
+// eval short-circuits left to right, so cheap conditions belong first.
+func (c *cond) eval(v *view) bool {
+	switch c.op {
+	case "AND":
+		for _, a := range c.args {
+			if !a.eval(v) {
+				return false
+			}
+		}
+		return true
+	case "OR":
+		for _, a := range c.args {
+			if a.eval(v) {
+				return true
+			}
+		}
+		return false
+	case "NOT":
+		return !c.args[0].eval(v)
+	}
+	return c.test(v)
+}
+
+// compiledCondition is the test of one named condition.
+type compiledCondition struct {
+	test  func(v *view) bool
+	words *wordsRule
+}
+
+func (m *Matcher) compileConditions(cs map[string]config.Condition) (map[string]compiledCondition, error) {
+	names := make([]string, 0, len(cs))
+	for n := range cs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	out := make(map[string]compiledCondition, len(cs))
+	keepCase := m.caseSensitive || m.checksum
+	for _, n := range names {
+		c := cs[n]
+		var cc compiledCondition
+		switch {
+		case c.Placeholder != nil:
+			if _, _, _, err := c.Placeholder.Parts(); err != nil {
+				return nil, fmt.Errorf("conditions.%s: %w", n, err)
+			}
+			r := compilePlaceholder(*c.Placeholder, keepCase)
+			cc.test = r.test
+		case c.Specific != nil:
+			r := compileSpecific(*c.Specific, m.format, keepCase)
+			cc.test = r.test
+		case c.Mask != nil:
+			pos, err := c.Mask.Positions()
+			if err == nil && len(pos) != m.format.BodyLen() {
+				err = fmt.Errorf("template has %d positions, want %d", len(pos), m.format.BodyLen())
+			}
+			if err != nil {
+				return nil, fmt.Errorf("conditions.%s: %w", n, err)
+			}
+			r := m.compileMask(pos)
+			cc.test = r.match
+		case c.Words != nil:
+			r := compileWords(*c.Words, m.checksum)
+			cc.words = &r
+			cc.test = func(v *view) bool {
+				_, _, ok := r.match(v)
+				return ok
+			}
+		case c.Edges != nil:
+			cc.test = newEdgesRule(*c.Edges).test
+		case c.Regexp != nil:
+			r, err := m.compileRegexp(*c.Regexp)
+			if err != nil {
+				return nil, fmt.Errorf("conditions.%s: %w", n, err)
+			}
+			cc.test = r.test
+		default:
+			return nil, fmt.Errorf("conditions.%s: no pattern", n)
+		}
+		out[n] = cc
+	}
+	return out, nil
+}
+
+func compileBoolRule(rc config.Rule, conds map[string]compiledCondition) (boolRule, error) {
+	e, err := rc.Expr()
+	if err != nil {
+		return boolRule{}, err
+	}
+	r := boolRule{name: rc.Name, kind: rc.Kind()}
+	seen := map[*wordsRule]bool{}
+	var build func(e *config.Expr, negated bool) (*cond, error)
+	build = func(e *config.Expr, negated bool) (*cond, error) {
+		if e.Op == "" {
+			cc, ok := conds[e.Name]
+			if !ok {
+				return nil, fmt.Errorf("no condition named %q", e.Name)
+			}
+			if w := cc.words; w != nil && !negated && !seen[w] {
+				seen[w] = true
+				r.words = append(r.words, w)
+			}
+			return &cond{test: cc.test}, nil
+		}
+		c := &cond{op: e.Op}
+		for _, a := range e.Args {
+			ac, err := build(a, negated != (e.Op == "NOT"))
+			if err != nil {
+				return nil, err
+			}
+			c.args = append(c.args, ac)
+		}
+		return c, nil
+	}
+	if r.expr, err = build(e, false); err != nil {
+		return boolRule{}, err
+	}
+	return r, nil
+}
@@ -0,0 +1,154 @@
+package patterns
+
+import (
+	"math"
+	"math/big"
+	"strings"
+
+	"WalletTools/internal/crypto"
+	"WalletTools/pkg/config"
+)
+
+// textModel is the chance of each body character of a text address format,
+// case-folded unless case-sensitive, at the first position of the body and
+// at any other. The tail of a base58check address is uniform thanks to the
+// checksum, but its head is pinned by the version byte: after the "T" of a
+// Tron address only about 22 characters can follow. Bech32 bodies are
+// uniform throughout. Solana addresses carry no checksum, but below their
+// leading digit the base58 digits of a random public key are near uniform
+// as well.
+type textModel struct {
+	first map[byte]float64
+	rest  map[byte]float64
+}
+
+func newTextModel(f config.AddressFormat, caseSensitive bool) *textModel {
+	fold := func(c byte) byte {
+		if !caseSensitive && c >= 'A' && c <= 'Z' {
+			return c + 'a' - 'A'
+		}
+		return c
+	}
+	alpha := f.Alphabet()
+	t := &textModel{first: map[byte]float64{}, rest: map[byte]float64{}}
+	for i := 0; i < len(alpha); i++ {
+		t.rest[fold(alpha[i])] += 1 / float64(len(alpha))
+	}
+
+	// The numbers behind base58check addresses span a range given by the
+	// version byte; the first body character is their digit skip places
+	// below the most significant one.
+	pow192 := new(big.Int).Lsh(big.NewInt(1), 192)
+	versioned := func(v int64) (*big.Int, *big.Int) {
+		return new(big.Int).Mul(big.NewInt(v), pow192), new(big.Int).Mul(big.NewInt(v+1), pow192)
+	}
+	switch f {
+	case config.FormatTron:
+		lo, hi := versioned(crypto.TronVersion)
+		t.addBase58Head(alpha, fold, lo, hi, 1, 1)
+	case config.FormatP2PKHTestnet:
+		lo, hi := versioned(int64(crypto.BTCTestnet.PKHVersion))
+		t.addBase58Head(alpha, fold, lo, hi, 1, 1)
+	case config.FormatP2PKH, config.FormatSolana:
+		// The zero version byte is the lead "1" of P2PKH; a payload
+		// starting with a zero byte adds a "1" in either format.
+		top := pow192
+		if f == config.FormatSolana {
+			top = new(big.Int).Lsh(big.NewInt(1), 256)
+		}
+		t.first[fold('1')] += 1.0 / 256
+		t.addBase58Head(alpha, fold, new(big.Int).Rsh(top, 8), top, 0, 255.0/256)
+	default:
+		t.first = t.rest
+	}
+	return t
+}
+
+// addBase58Head adds share times the distribution of the digit skip places
+// below the leading one, over the numbers of [lo, hi) in base58.
+func (t *textModel) addBase58Head(alpha string, fold func(byte) byte, lo, hi *big.Int, skip int, share float64) {
+	span, _ := new(big.Float).SetInt(new(big.Int).Sub(hi, lo)).Float64()
+	b58 := big.NewInt(58)
+	unit := new(big.Int).Exp(b58, big.NewInt(int64(skip)), nil)
+	// Numbers of L digits lie in [58^(L-1), 58^L); the wanted digit has the
+	// weight w = 58^(L-1-skip) there.
+	for low, top := big.NewInt(1), big.NewInt(58); low.Cmp(hi) < 0; low, top = top, new(big.Int).Mul(top, b58) {
+		a, b := maxInt(low, lo), minInt(top, hi)
+		w := new(big.Int).Div(low, unit)
+		if a.Cmp(b) >= 0 || w.Sign() == 0 {
+			continue
+		}
+		qHi := new(big.Int).Div(new(big.Int).Sub(b, big.NewInt(1)), w)
+		for q := new(big.Int).Div(a, w); q.Cmp(qHi) <= 0; q.Add(q, big.NewInt(1)) {
+			x := maxInt(new(big.Int).Mul(q, w), a)
+			y := minInt(new(big.Int).Mul(new(big.Int).Add(q, big.NewInt(1)), w), b)
+			n, _ := new(big.Float).SetInt(y.Sub(y, x)).Float64()
+			d := new(big.Int).Mod(q, b58).Int64()
+			t.first[fold(alpha[d])] += share * n / span
+		}
+	}
+}
+
+func maxInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) > 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
+
+func minInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
+
+// literal is the chance that s is found at the start (atStart) or the end
+// of the body.
+func (t *textModel) literal(s string, atStart bool) float64 {
+	pr := 1.0
+	for i := 0; i < len(s); i++ {
+		if i == 0 && atStart {
+			pr *= t.first[s[i]]
+		} else {
+			pr *= t.rest[s[i]]
+		}
+	}
+	return pr
+}
+
+// run is the chance that the body starts (atStart) or ends with n equal
+// characters.
+func (t *textModel) run(n int, atStart bool) float64 {
+	if n <= 1 {
+		return 1
+	}
+	pr := 0.0
+	for c, p := range t.rest {
+		head := p
+		if atStart {
+			head = t.first[c]
+		}
+		pr += head * math.Pow(p, float64(n-1))
+	}
+	return pr
+}
+
+// literalProb and runProb dispatch between the hex model of EVM addresses
+// and the text model of other formats.
+func (m *Matcher) literalProb(s string, atStart bool) float64 {
+	if m.text == nil {
+		return literalProb(s, m.checksum)
+	}
+	if !m.caseSensitive {
+		s = strings.ToLower(s)
+	}
+	return m.text.literal(s, atStart)
+}
+
+func (m *Matcher) runProb(n int, atStart bool) float64 {
+	if m.text == nil {
+		return runProb(n, m.checksum)
+	}
+	return m.text.run(n, atStart)
+}
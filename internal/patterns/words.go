@@ -0,0 +1,191 @@
+package patterns
+
+import (
+	"math"
+
+	"WalletTools/pkg/config"
+)
+
+// wordsRule finds the words of a dictionary with an Aho-Corasick automaton
+// built once per run: a single pass over the body finds every word, however
+// large the dictionary. The automaton runs on the plain view; under
+// checksum_case the words keep their case and each candidate is confirmed on
+// the EIP-55 casing.
+type wordsRule struct {
+	words          []string
+	cased          bool
+	prefix, suffix bool
+
+	class [256]uint8 // body byte -> column; 0 for bytes of no word
+	cols  int
+	next  []int32   // state*cols + column -> state, failure links folded in
+	depth []uint8   // length of the state's trie path
+	outs  [][]int32 // words ending in the state
+	link  []int32   // nearest proper suffix state with words, -1 for none
+	rule
+}
+
+func compileWords(p config.WordsPattern, cased bool) wordsRule {
+	r := wordsRule{
+		words:  p.Words,
+		cased:  cased,
+		prefix: p.Side == "prefix",
+		suffix: p.Side == "suffix",
+		cols:   1,
+	}
+	key := func(c byte) byte {
+		if cased && c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		return c
+	}
+	for _, w := range r.words {
+		for i := 0; i < len(w); i++ {
+			if c := key(w[i]); r.class[c] == 0 {
+				r.class[c] = uint8(r.cols)
+				r.cols++
+			}
+		}
+	}
+	if cased {
+		// The plain view is lower-case: its letters take the column of
+		// their folded twin.
+		for c := 'A'; c <= 'Z'; c++ {
+			r.class[c] = r.class[c+'a'-'A']
+		}
+	}
+
+	// The trie, with -1 for missing edges.
+	newState := func(depth int) int32 {
+		for range r.cols {
+			r.next = append(r.next, -1)
+		}
+		r.depth = append(r.depth, uint8(depth))
+		r.outs = append(r.outs, nil)
+		return int32(len(r.depth) - 1)
+	}
+	newState(0)
+	for wi, w := range r.words {
+		s := int32(0)
+		for i := 0; i < len(w); i++ {
+			e := int(s)*r.cols + int(r.class[key(w[i])])
+			if r.next[e] < 0 {
+				t := newState(i + 1)
+				r.next[e] = t
+			}
+			s = r.next[e]
+		}
+		r.outs[s] = append(r.outs[s], int32(wi))
+	}
+
+	// Breadth first, every state's failure row is complete before it is
+	// needed: missing edges take the failure state's.
+	fail := make([]int32, len(r.depth))
+	r.link = make([]int32, len(r.depth))
+	r.link[0] = -1
+	queue := []int32{0}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for c := 0; c < r.cols; c++ {
+			e := int(s)*r.cols + c
+			t := r.next[e]
+			if t < 0 {
+				if s == 0 {
+					r.next[e] = 0
+				} else {
+					r.next[e] = r.next[int(fail[s])*r.cols+c]
+				}
+				continue
+			}
+			if s != 0 {
+				fail[t] = r.next[int(fail[s])*r.cols+c]
+			}
+			if f := fail[t]; len(r.outs[f]) > 0 {
+				r.link[t] = f
+			} else {
+				r.link[t] = r.link[f]
+			}
+			queue = append(queue, t)
+		}
+	}
+	return r
+}
+
+// match returns the longest word found in the body, the first one of that
+// length, and its position there.
+func (r *wordsRule) match(v *view) (word, pos int, ok bool) {
+	body := v.body()
+	word = -1
+	s := int32(0)
+	for i, c := range body {
+		s = r.next[int(s)*r.cols+int(r.class[c])]
+		if r.prefix && int(r.depth[s]) != i+1 {
+			break // no word starts at the beginning any more
+		}
+		if r.suffix && i != len(body)-1 {
+			continue
+		}
+		for o := s; o >= 0; o = r.link[o] {
+			for _, w := range r.outs[o] {
+				n := len(r.words[w])
+				start := i + 1 - n
+				if r.prefix && start != 0 || word >= 0 && n <= len(r.words[word]) {
+					continue
+				}
+				if r.cased && string(v.casedBody()[start:i+1]) != r.words[w] {
+					continue
+				}
+				word, pos = int(w), start
+			}
+		}
+	}
+	return word, pos, word >= 0
+}
+
+// implied reports whether another, shorter word matches wherever word w
+// does: w contains it, or for anchored rules starts or ends with it.
+func (r *wordsRule) implied(w int) bool {
+	word := r.words[w]
+	s := int32(0)
+	for i := 0; i < len(word); i++ {
+		s = r.next[int(s)*r.cols+int(r.class[word[i]])]
+		for o := s; o >= 0; o = r.link[o] {
+			for _, x := range r.outs[o] {
+				start := i + 1 - len(r.words[x])
+				if int(x) == w || r.prefix && start != 0 || r.suffix && i != len(word)-1 {
+					continue
+				}
+				if word[start:i+1] == r.words[x] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// wordsProb adds up the chances of the words not implied by shorter ones.
+// Anchored, they exclude each other and the sum is exact; anywhere in the
+// body, their occurrences are taken as a Poisson process.
+func (m *Matcher) wordsProb(r *wordsRule) float64 {
+	sum := 0.0
+	for w, word := range r.words {
+		if r.implied(w) {
+			continue
+		}
+		switch {
+		case r.prefix:
+			sum += m.literalProb(word, true)
+		case r.suffix:
+			sum += m.literalProb(word, false)
+		default:
+			inner := max(m.format.BodyLen()-len(word), 0)
+			sum += m.literalProb(word, true) + float64(inner)*m.literalProb(word, false)
+		}
+	}
+	if r.prefix || r.suffix {
+		return min(sum, 1)
+	}
+	return -math.Expm1(-sum)
+}
@@ -3,6 +3,7 @@ package appcfg
 import (
 	"fmt"
 	"os"
+	"regexp"
 
 	"gopkg.in/yaml.v3"
 )
@@ -35,3 +36,32 @@ func Load(path string) (*Config, error) {
 	}
 	return &c, nil
 }
+
+// coresValue matches the key and value of the cores line, plus the start of
+// a comment right after the value.
+var coresValue = regexp.MustCompile(`(?m)^cores:[ \t]*(?:"[^"\n]*"|'[^'\n]*'|[^\s#]*)([ \t]*#)?`)
+
+// SaveCores writes cores into the app config at path, keeping the rest of
+// the file and its comments, the one on the cores line included, as they are.
+func SaveCores(path string, cores int) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read app config %q: %w", path, err)
+	}
+	line := fmt.Sprintf("cores: %d", cores)
+	if coresValue.Match(b) {
+		b = coresValue.ReplaceAllFunc(b, func(m []byte) []byte {
+			comment := coresValue.FindSubmatch(m)[1]
+			if len(comment) > 0 && comment[0] == '#' {
+				comment = []byte(" #") // a comment needs a space before it
+			}
+			return append([]byte(line), comment...)
+		})
+	} else {
+		if len(b) > 0 && b[len(b)-1] != '\n' {
+			b = append(b, '\n')
+		}
+		b = append(b, line+"\n"...)
+	}
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,44 @@
+package appcfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveCores(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"language: en\ncores: 4\nlog_level: info\n", "language: en\ncores: 12\nlog_level: info\n"},
+		{"cores: 4   # set by the benchmark\n", "cores: 12   # set by the benchmark\n"},
+		{"cores: \"4\" # quoted\n", "cores: 12 # quoted\n"},
+		{"cores:\n", "cores: 12\n"},
+		{"cores:# odd\n", "cores: 12 # odd\n"},
+		{"# cores: 4\nlanguage: en", "# cores: 4\nlanguage: en\ncores: 12\n"},
+		{"", "cores: 12\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "app.yaml")
+		if err := os.WriteFile(path, []byte(tt.in), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveCores(path, 12); err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.in, b, tt.want)
+		}
+		c, err := Load(path)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.in, err)
+		}
+		if c.Cores != 12 {
+			t.Errorf("%q: loads as %d cores", tt.in, c.Cores)
+		}
+	}
+}
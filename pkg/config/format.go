@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AddressFormat is the text form of the addresses that patterns are matched
+// against. Most formats have a fixed lead ("0x", "T", "bc1q") followed by the
+// body that the patterns see; Solana addresses are all body.
+//
+// Bitcoin formats are named after the script type, with a "-testnet" or
+// "-regtest" suffix outside mainnet; regtest P2PKH addresses are the testnet
+// ones. Testnet P2PKH addresses start with "m" or "n": a lead of one
+// character that is not fixed.
+//
+// Cosmos-SDK formats carry the chain's human-readable part: "cosmos" is the
+// Cosmos Hub, "cosmos-osmo" the "osmo1…" accounts of Osmosis, and so on.
+type AddressFormat string
+
+const (
+	FormatEVM  AddressFormat = "evm"  // "0x" + 40 hex digits
+	FormatTron AddressFormat = "tron" // base58check of 0x41 ++ the EVM address
+
+	FormatP2PKH         AddressFormat = "p2pkh"          // 1…
+	FormatP2PKHTestnet  AddressFormat = "p2pkh-testnet"  // m… or n…
+	FormatP2WPKH        AddressFormat = "p2wpkh"         // bc1q…
+	FormatP2WPKHTestnet AddressFormat = "p2wpkh-testnet" // tb1q…
+	FormatP2WPKHRegtest AddressFormat = "p2wpkh-regtest" // bcrt1q…
+	FormatP2TR          AddressFormat = "p2tr"           // bc1p…
+	FormatP2TRTestnet   AddressFormat = "p2tr-testnet"   // tb1p…
+	FormatP2TRRegtest   AddressFormat = "p2tr-regtest"   // bcrt1p…
+
+	FormatSolana AddressFormat = "solana" // base58 of the ed25519 public key
+
+	FormatCosmos AddressFormat = "cosmos" // cosmos1…, see CosmosFormat for other chains
+)
+
+// maxHRPLen keeps Cosmos addresses within the 90 characters of bech32.
+const maxHRPLen = 90 - 1 - 38
+
+// Base58Alphabet is the Bitcoin base58 alphabet: no 0, O, I or l.
+const Base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Bech32Alphabet is the lower-case alphabet of segwit addresses: no 1, b, i
+// or o.
+const Bech32Alphabet = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Formats lists the supported address formats.
+func Formats() []AddressFormat {
+	return []AddressFormat{
+		FormatEVM, FormatTron,
+		FormatP2PKH, FormatP2PKHTestnet,
+		FormatP2WPKH, FormatP2WPKHTestnet, FormatP2WPKHRegtest,
+		FormatP2TR, FormatP2TRTestnet, FormatP2TRRegtest,
+		FormatSolana, FormatCosmos,
+	}
+}
+
+// CosmosFormat is the Cosmos-SDK format of the chain whose account addresses
+// start with hrp + "1".
+func CosmosFormat(hrp string) (AddressFormat, error) {
+	if hrp == "" || len(hrp) > maxHRPLen {
+		return "", fmt.Errorf("human-readable part must have 1 to %d characters", maxHRPLen)
+	}
+	for _, r := range hrp {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return "", fmt.Errorf("human-readable part %q: only a-z and 0-9 are allowed", hrp)
+		}
+	}
+	if hrp == "cosmos" {
+		return FormatCosmos, nil
+	}
+	return FormatCosmos + AddressFormat("-"+hrp), nil
+}
+
+// cosmosCoinTypes are the SLIP-44 coin types that the wallets of Cosmos-SDK
+// chains derive their keys under, by human-readable part.
+var cosmosCoinTypes = map[string]int{
+	"cosmos": 118, "osmo": 118, "juno": 118, "akash": 118, "stars": 118,
+	"celestia": 118, "neutron": 118, "stride": 118, "axelar": 118, "sei": 118,
+	"terra": 330, "cro": 394, "band": 494, "kava": 459, "secret": 529,
+	"persistence": 750,
+}
+
+// CoinType is the SLIP-44 coin type of the Cosmos chain of f, if known.
+func (f AddressFormat) CoinType() (int, bool) {
+	if f.Script() != "cosmos" {
+		return 0, false
+	}
+	ct, ok := cosmosCoinTypes[f.HRP()]
+	return ct, ok
+}
+
+// ParseFormat accepts a format name, or "cosmos-<hrp>"; empty means
+// FormatEVM.
+func ParseFormat(s string) (AddressFormat, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return FormatEVM, nil
+	}
+	if hrp, ok := strings.CutPrefix(s, string(FormatCosmos)+"-"); ok {
+		return CosmosFormat(hrp)
+	}
+	for _, f := range Formats() {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown address format %q", s)
+}
+
+// Script is the Bitcoin script type of f ("p2pkh", "p2wpkh", "p2tr"),
+// "cosmos" for every Cosmos-SDK chain, or f itself for the other formats.
+func (f AddressFormat) Script() string {
+	s, _, _ := strings.Cut(string(f), "-")
+	return s
+}
+
+// Bitcoin reports whether f is a Bitcoin address format.
+func (f AddressFormat) Bitcoin() bool {
+	switch f.Script() {
+	case "p2pkh", "p2wpkh", "p2tr":
+		return true
+	}
+	return false
+}
+
+// KeyHash reports whether the addresses of f encode the Hash160 of the
+// compressed public key rather than the EVM address.
+func (f AddressFormat) KeyHash() bool {
+	switch f.Script() {
+	case "p2pkh", "p2wpkh", "cosmos":
+		return true
+	}
+	return false
+}
+
+// Net is the Bitcoin network of f: "mainnet", "testnet" or "regtest".
+func (f AddressFormat) Net() string {
+	if _, net, ok := strings.Cut(string(f), "-"); ok {
+		return net
+	}
+	return "mainnet"
+}
+
+// HRP is the human-readable part of bech32 addresses in f: the network's
+// for segwit, the chain's for Cosmos.
+func (f AddressFormat) HRP() string {
+	if f.Script() == "cosmos" {
+		if _, hrp, ok := strings.Cut(string(f), "-"); ok {
+			return hrp
+		}
+		return "cosmos"
+	}
+	switch f.Net() {
+	case "testnet":
+		return "tb"
+	case "regtest":
+		return "bcrt"
+	}
+	return "bc"
+}
+
+// Lead is the fixed start of every address, outside the body; empty for
+// testnet P2PKH, see LeadLen.
+func (f AddressFormat) Lead() string {
+	switch f {
+	case FormatEVM:
+		return "0x"
+	case FormatTron:
+		return "T"
+	case FormatP2PKH:
+		return "1"
+	case FormatP2PKHTestnet:
+		return ""
+	}
+	switch f.Script() {
+	case "p2wpkh":
+		return f.HRP() + "1q"
+	case "p2tr":
+		return f.HRP() + "1p"
+	case "cosmos":
+		return f.HRP() + "1"
+	}
+	return ""
+}
+
+// LeadLen is the number of characters before the body.
+func (f AddressFormat) LeadLen() int {
+	if f == FormatP2PKHTestnet {
+		return 1
+	}
+	return len(f.Lead())
+}
+
+// Alphabet holds the characters of an address body.
+func (f AddressFormat) Alphabet() string {
+	switch f.Script() {
+	case "tron", "p2pkh", "solana":
+		return Base58Alphabet
+	case "p2wpkh", "p2tr", "cosmos":
+		return Bech32Alphabet
+	}
+	return "0123456789abcdefABCDEF"
+}
+
+// BodyLen is the longest number of characters after the lead; legacy P2PKH
+// and Solana bodies are shorter when the payload starts with small values.
+func (f AddressFormat) BodyLen() int {
+	switch f {
+	case FormatTron, FormatP2PKH, FormatP2PKHTestnet:
+		return 33
+	}
+	switch f.Script() {
+	case "p2wpkh", "cosmos":
+		return 32 + 6 // 160 bits in 5-bit groups, checksum
+	case "p2tr":
+		return 52 + 6
+	case "solana":
+		return 44
+	}
+	return 40
+}
+
+// PayloadLen is the size of what the address encodes: the 20-byte address
+// or key hash, or the 32-byte taproot output key or ed25519 public key.
+func (f AddressFormat) PayloadLen() int {
+	switch f.Script() {
+	case "p2tr", "solana":
+		return 32
+	}
+	return 20
+}
+
+// checkAlphabet reports the first character of s outside f's alphabet; in
+// either case unless caseSensitive, so "l" passes base58 thanks to "L".
+func (f AddressFormat) checkAlphabet(s string, caseSensitive bool) error {
+	for _, r := range s {
+		ok := strings.ContainsRune(f.Alphabet(), r)
+		if !ok && !caseSensitive {
+			ok = strings.ContainsRune(f.Alphabet(), unicode.ToUpper(r)) || strings.ContainsRune(f.Alphabet(), unicode.ToLower(r))
+		}
+		if !ok {
+			return fmt.Errorf("%q is not a %s address character", r, f)
+		}
+	}
+	return nil
+}
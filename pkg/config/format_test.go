@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestCoinType(t *testing.T) {
+	tests := []struct {
+		format string
+		want   int
+		ok     bool
+	}{
+		{"cosmos", 118, true},
+		{"cosmos-osmo", 118, true},
+		{"cosmos-terra", 330, true},
+		{"cosmos-kava", 459, true},
+		{"cosmos-secret", 529, true},
+		{"cosmos-unknownchain", 0, false},
+		{"evm", 0, false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFormat(tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := f.CoinType(); got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %d, %v, want %d, %v", tt.format, got, ok, tt.want, tt.ok)
+		}
+	}
+}
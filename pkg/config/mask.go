@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaskPattern pins arbitrary positions of the address body with a template
+// of exactly one entry per body character: a literal character, "?" for any
+// character, or a class like "[0-9]" or "[a-fA-F]" of characters and ranges.
+// "dead????????????????????????????????beef" is a 40-digit EVM mask.
+type MaskPattern struct {
+	Template string `yaml:"template"`
+	Final    bool   `yaml:"final"`
+	MaxHits  int    `yaml:"max_hits"`
+}
+
+// MaskPos is one position of a mask template: the characters allowed there,
+// all of them when Chars is empty.
+type MaskPos struct {
+	Chars string
+	Class bool // from a [...] class rather than a literal
+}
+
+// Positions parses the template. Classes are expanded as written; the
+// characters outside the address alphabet are dropped by validate.
+func (p MaskPattern) Positions() ([]MaskPos, error) {
+	var out []MaskPos
+	t := p.Template
+	for i := 0; i < len(t); i++ {
+		switch c := t[i]; c {
+		case '?':
+			out = append(out, MaskPos{})
+		case '[':
+			end := strings.IndexByte(t[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed class at character %d", i+1)
+			}
+			chars, err := expandClass(t[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("class %s: %w", t[i:i+end+1], err)
+			}
+			out = append(out, MaskPos{Chars: chars, Class: true})
+			i += end
+		case ']':
+			return nil, fmt.Errorf("unopened ] at character %d", i+1)
+		default:
+			out = append(out, MaskPos{Chars: string(c)})
+		}
+	}
+	return out, nil
+}
+
+// expandClass lists the characters of a class body like "0-9a".
+func expandClass(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("empty class")
+	}
+	if s[0] == '^' {
+		return "", errors.New("negated classes are not supported")
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i+2 < len(s) && s[i+1] == '-' {
+			lo, hi := s[i], s[i+2]
+			if lo > hi {
+				return "", fmt.Errorf("range %c-%c is reversed", lo, hi)
+			}
+			for c := lo; c <= hi; c++ {
+				if !strings.ContainsRune(b.String(), rune(c)) {
+					b.WriteByte(c)
+				}
+			}
+			i += 2
+			continue
+		}
+		if !strings.ContainsRune(b.String(), rune(s[i])) {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// validateMask checks a template against the body of format: one entry per
+// body character, each literal in the alphabet and each class with at least
+// one character of it. Positions keeps the classes as written and the
+// matcher intersects them with the alphabet again.
+func validateMask(p MaskPattern, format AddressFormat, caseSensitive bool) error {
+	pos, err := p.Positions()
+	if err != nil {
+		return err
+	}
+	if len(pos) != format.BodyLen() {
+		return fmt.Errorf("template has %d positions, a %s address body has %d", len(pos), format, format.BodyLen())
+	}
+	for i, mp := range pos {
+		if !mp.Class {
+			if err := format.checkAlphabet(mp.Chars, caseSensitive); err != nil {
+				return fmt.Errorf("position %d: %w", i+1, err)
+			}
+			continue
+		}
+		ok := false
+		for _, r := range mp.Chars {
+			if format.checkAlphabet(string(r), caseSensitive) == nil {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("position %d: class has no %s address character", i+1, format)
+		}
+	}
+	return nil
+}
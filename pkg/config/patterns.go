@@ -4,61 +4,164 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
+	"WalletTools/pkg/logx"
+
 	"gopkg.in/yaml.v3"
 )
 
 // PatternsConfig describes the configuration for finding patterns.
+//
+// All kinds are matched against the address body: the 40 hex digits without
+// "0x". A leading "0x" in a specific prefix is accepted and stripped; regexps
+// see "0x" + body only when with_prefix is set. Configs without version: 2
+// predate this and get migration warnings on load. For other address formats
+// (see LoadFor) the body is what follows the format's lead, e.g. the 33
+// base58 characters after the "T" of a Tron address or the bech32 characters
+// after "bc1q".
+//
+// Hex digits have no case of their own, so EVM patterns are matched in lower
+// case unless checksum_case asks for the EIP-55 casing: then every letter of
+// a pattern must come out in exactly the case written, which halves the odds
+// per letter. case_sensitive is for the formats whose alphabet has cases.
+//
+// MaxHits (max_hits) on any pattern disables it after that many results
+// while the other patterns keep running; 0 means unlimited.
 type PatternsConfig struct {
-	Symbols       string             `yaml:"symbols"`
-	CaseSensitive bool               `yaml:"case_sensitive"` // NEW: true -> учитывать регистр
-	Symmetric     []SymmetricPattern `yaml:"symmetric"`
-	Specific      []SpecificPattern  `yaml:"specific"`
-	Edges         EdgeConfig         `yaml:"edges"`
-	Regexp        []RegexpPattern    `yaml:"regexp"`
+	Format AddressFormat `yaml:"-"` // set by LoadFor
+
+	Version       int                  `yaml:"version"`
+	Symbols       string               `yaml:"symbols"`
+	CaseSensitive bool                 `yaml:"case_sensitive"` // NEW: true -> учитывать регистр
+	ChecksumCase  bool                 `yaml:"checksum_case"`  // EVM: letters must match the EIP-55 casing
+	Symmetric     []SymmetricPattern   `yaml:"symmetric"`
+	Placeholder   []PlaceholderPattern `yaml:"placeholder"`
+	Specific      []SpecificPattern    `yaml:"specific"`
+	Mask          []MaskPattern        `yaml:"mask"`
+	Words         []WordsPattern       `yaml:"words"`
+	Edges         EdgeConfig           `yaml:"edges"`
+	Regexp        []RegexpPattern      `yaml:"regexp"`
+	Mnemonic      *MnemonicPattern     `yaml:"mnemonic"`
+
+	// Conditions are named patterns that only count through Rules.
+	Conditions map[string]Condition `yaml:"conditions"`
+	Rules      []Rule               `yaml:"rules"`
 }
 
+// SymmetricPattern asks for a run of one repeated character at each end of
+// the body, the same at both when the sides share a placeholder letter; see
+// Placeholder for the general form it is matched in.
 type SymmetricPattern struct {
-	Prefix string `yaml:"prefix"`
-	Suffix string `yaml:"suffix"`
-	Final  bool   `yaml:"final"`
+	Prefix  string `yaml:"prefix"`
+	Suffix  string `yaml:"suffix"`
+	Final   bool   `yaml:"final"`
+	MaxHits int    `yaml:"max_hits"`
 }
 
 type SpecificPattern struct {
-	Prefix string `yaml:"prefix"`
-	Suffix string `yaml:"suffix"`
-	Final  bool   `yaml:"final"`
+	Prefix  string `yaml:"prefix"`
+	Suffix  string `yaml:"suffix"`
+	Final   bool   `yaml:"final"`
+	MaxHits int    `yaml:"max_hits"`
 }
 
 type EdgeConfig struct {
 	MinCount int    `yaml:"minCount"`
 	Side     string `yaml:"side"` // any|prefix|suffix
 	Final    bool   `yaml:"final"`
+	MaxHits  int    `yaml:"max_hits"`
 }
 
 type RegexpPattern struct {
-	Pattern string `yaml:"pattern"`
-	Final   bool   `yaml:"final"`
+	Pattern    string `yaml:"pattern"`
+	WithPrefix bool   `yaml:"with_prefix"` // match against "0x" + body instead of the body
+	Final      bool   `yaml:"final"`
+	MaxHits    int    `yaml:"max_hits"`
+}
+
+// MnemonicPattern constrains the words of the phrases generated in mnemonic
+// mode. Phrases are drawn from the allowed words directly, so the check costs
+// nothing per phrase, but every rule removes entropy; the run reports how
+// much and refuses phrases weaker than mnemonic.MinBits without allow_weak.
+// Without any address pattern each such phrase is itself a hit.
+type MnemonicPattern struct {
+	Fixed     map[int]string `yaml:"fixed"`      // 1-based position -> word
+	Allow     []string       `yaml:"allow"`      // every word from this list
+	Deny      []string       `yaml:"deny"`       // no word from this list
+	MaxLength int            `yaml:"max_length"` // every word at most this many letters
+	AllowWeak bool           `yaml:"allow_weak"`
+	Final     bool           `yaml:"final"`
+	MaxHits   int            `yaml:"max_hits"`
+}
+
+// HasAddressPatterns reports whether c has any pattern over addresses, as
+// opposed to the mnemonic section alone.
+func (c *PatternsConfig) HasAddressPatterns() bool {
+	return len(c.Symmetric) > 0 || len(c.Placeholder) > 0 || len(c.Specific) > 0 || len(c.Mask) > 0 || len(c.Words) > 0 || c.Edges.MinCount > 0 || len(c.Regexp) > 0 || len(c.Rules) > 0
 }
 
+// Load reads a config for EVM addresses.
 func Load(path string) (*PatternsConfig, error) {
+	return LoadFor(path, FormatEVM)
+}
+
+// LoadFor reads a config whose patterns apply to addresses in format. The
+// specific literals are checked against the format's alphabet, and outside
+// EVM the symbols as well: a hex-minded pattern like "0000" could never match
+// base58, nor "beeg" an EVM address.
+func LoadFor(path string, format AddressFormat) (*PatternsConfig, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open config %q: %w", path, err)
 	}
 	defer f.Close()
 
-	var cfg PatternsConfig
+	cfg := PatternsConfig{Format: format}
 	dec := yaml.NewDecoder(f)
 	if err := dec.Decode(&cfg); err != nil {
 		return nil, fmt.Errorf("decode yaml %q: %w", path, err)
 	}
 
+	keepCase := cfg.CaseSensitive || format == FormatEVM && cfg.ChecksumCase
+	dicts := map[string]*WordsPattern{}
+	for i := range cfg.Words {
+		dicts[fmt.Sprintf("words[%d]", i)] = &cfg.Words[i]
+	}
+	for name, cond := range cfg.Conditions {
+		if cond.Words != nil {
+			dicts["conditions."+name] = cond.Words
+		}
+	}
+	for label, wp := range dicts {
+		skipped, err := loadWords(wp, filepath.Dir(path), format, keepCase)
+		if err != nil {
+			return nil, fmt.Errorf("config %q: %s: %w", path, label, err)
+		}
+		if len(skipped) > 0 {
+			logx.S().Warnw("words that cannot appear in an address are skipped",
+				"config", path, "pattern", label, "format", format,
+				"skipped", len(skipped), "first", skipped[0])
+		}
+	}
+
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("config validation %q: %w", path, err)
 	}
 
+	// Only EVM configs ever saw the "0x" + body view.
+	if format == FormatEVM {
+		for _, w := range migrate(&cfg) {
+			logx.S().Warnw("patterns config migration", "config", path, "warning", w)
+		}
+		if cfg.CaseSensitive && !cfg.ChecksumCase {
+			logx.S().Warnw("case_sensitive on EVM addresses compares the EIP-55 checksum casing; set checksum_case: true to say so",
+				"config", path, "cost", "every letter of a pattern halves its odds")
+		}
+	}
+
 	return &cfg, nil
 }
 
@@ -80,7 +183,13 @@ func validate(c *PatternsConfig) error {
 		}
 	}
 
+	if c.Edges.MaxHits < 0 {
+		return errors.New("edges.max_hits must be >= 0")
+	}
 	for i, sp := range c.Symmetric {
+		if sp.MaxHits < 0 {
+			return fmt.Errorf("symmetric[%d].max_hits must be >= 0", i)
+		}
 		if err := validateOnlyXY(sp.Prefix); err != nil {
 			return fmt.Errorf("symmetric[%d].prefix: %w", i, err)
 		}
@@ -89,13 +198,96 @@ func validate(c *PatternsConfig) error {
 		}
 	}
 
-	if len(c.Symmetric) == 0 && len(c.Specific) == 0 && c.Edges.MinCount == 0 && len(c.Regexp) == 0 {
-		return errors.New("no patterns defined: symmetric, specific, edges, regexp are all empty")
+	for i, pp := range c.Placeholder {
+		if pp.MaxHits < 0 {
+			return fmt.Errorf("placeholder[%d].max_hits must be >= 0", i)
+		}
+		if err := validatePlaceholder(pp, c.Format, c.CaseSensitive); err != nil {
+			return fmt.Errorf("placeholder[%d]: %w", i, err)
+		}
+	}
+
+	for i, sp := range c.Specific {
+		if sp.MaxHits < 0 {
+			return fmt.Errorf("specific[%d].max_hits must be >= 0", i)
+		}
+	}
+
+	for i, mp := range c.Mask {
+		if mp.MaxHits < 0 {
+			return fmt.Errorf("mask[%d].max_hits must be >= 0", i)
+		}
+		if err := validateMask(mp, c.Format, c.CaseSensitive); err != nil {
+			return fmt.Errorf("mask[%d]: %w", i, err)
+		}
+	}
+
+	for i, wp := range c.Words {
+		if err := validateWords(wp); err != nil {
+			return fmt.Errorf("words[%d]: %w", i, err)
+		}
+	}
+
+	for i, rp := range c.Regexp {
+		if rp.MaxHits < 0 {
+			return fmt.Errorf("regexp[%d].max_hits must be >= 0", i)
+		}
+		if _, err := regexp.Compile(rp.Pattern); err != nil {
+			return fmt.Errorf("regexp[%d].pattern: %w", i, err)
+		}
+	}
+
+	if mp := c.Mnemonic; mp != nil {
+		if mp.MaxHits < 0 {
+			return errors.New("mnemonic.max_hits must be >= 0")
+		}
+		if mp.MaxLength < 0 {
+			return errors.New("mnemonic.max_length must be >= 0")
+		}
+		for pos := range mp.Fixed {
+			if pos < 1 || pos > 24 {
+				return fmt.Errorf("mnemonic.fixed: position %d is not in 1..24", pos)
+			}
+		}
+	}
+
+	if err := validateRules(c); err != nil {
+		return err
+	}
+
+	if c.ChecksumCase && c.Format != FormatEVM {
+		return fmt.Errorf("checksum_case is the EIP-55 casing of EVM addresses; %s addresses take case_sensitive", c.Format)
+	}
+
+	if c.Format != FormatEVM {
+		if err := c.Format.checkAlphabet(strings.Join(strings.Fields(c.Symbols), ""), c.CaseSensitive); err != nil {
+			return fmt.Errorf("symbols: %w", err)
+		}
+	}
+	for i, sp := range c.Specific {
+		if err := c.checkSpecific(sp); err != nil {
+			return fmt.Errorf("specific[%d]: %w", i, err)
+		}
+	}
+
+	if !c.HasAddressPatterns() && c.Mnemonic == nil {
+		return errors.New("no patterns defined: symmetric, placeholder, specific, mask, words, edges, regexp, rules, mnemonic are all empty")
 	}
 
 	return nil
 }
 
+// checkSpecific checks the literals of sp against the alphabet of c's format,
+// hex digits for EVM, where the prefix may still carry the "0x" that migrate
+// strips.
+func (c *PatternsConfig) checkSpecific(sp SpecificPattern) error {
+	pre := sp.Prefix
+	if c.Format == FormatEVM && strings.HasPrefix(strings.ToLower(pre), "0x") {
+		pre = pre[2:]
+	}
+	return c.Format.checkAlphabet(pre+sp.Suffix, c.CaseSensitive)
+}
+
 func validateOnlyXY(s string) error {
 	if s == "" {
 		return errors.New("must be non-empty and contain only X/Y")
@@ -108,3 +300,59 @@ func validateOnlyXY(s string) error {
 	}
 	return nil
 }
+
+// BodyViewVersion is the first config version matched against the address
+// body instead of "0x" + body.
+const BodyViewVersion = 2
+
+// migrate normalizes c to the body-only address view and, for configs older
+// than BodyViewVersion, describes every pattern whose meaning differs from the
+// time addresses were matched with their "0x" prefix.
+func migrate(c *PatternsConfig) []string {
+	legacy := c.Version < BodyViewVersion
+	var warns []string
+	for i := range c.Specific {
+		sp := &c.Specific[i]
+		switch {
+		case strings.HasPrefix(strings.ToLower(sp.Prefix), "0x"):
+			sp.Prefix = sp.Prefix[2:]
+		case legacy && sp.Prefix == "0":
+			warns = append(warns, fmt.Sprintf(
+				"specific[%d].prefix \"0\" now asks for a body starting with 0; compared against \"0x\" before, it matched every address", i))
+		case legacy && sp.Prefix != "":
+			warns = append(warns, fmt.Sprintf(
+				"specific[%d].prefix %q now matches the address body; it was compared against \"0x\" before and could not match", i, sp.Prefix))
+		}
+	}
+
+	for _, cond := range c.Conditions {
+		if sp := cond.Specific; sp != nil && strings.HasPrefix(strings.ToLower(sp.Prefix), "0x") {
+			sp.Prefix = sp.Prefix[2:]
+		}
+	}
+
+	if !legacy {
+		return nil
+	}
+
+	for i, sp := range c.Symmetric {
+		warns = append(warns, fmt.Sprintf(
+			"symmetric[%d] prefix %q now starts at the address body; it was compared against \"0x\" before", i, sp.Prefix))
+	}
+
+	if c.Edges.MinCount > 1 && (c.Edges.Side == "prefix" || c.Edges.Side == "any") {
+		warns = append(warns, fmt.Sprintf(
+			"edges prefix run now starts at the address body; it always counted the single \"0\" of \"0x\" before (minCount=%d)", c.Edges.MinCount))
+	}
+
+	for i, rp := range c.Regexp {
+		if rp.WithPrefix {
+			continue
+		}
+		if strings.Contains(rp.Pattern, "^") || strings.Contains(strings.ToLower(rp.Pattern), "0x") {
+			warns = append(warns, fmt.Sprintf(
+				"regexp[%d] %q now matches the address body without \"0x\"; set with_prefix: true to keep matching \"0x\" + body", i, rp.Pattern))
+		}
+	}
+	return warns
+}
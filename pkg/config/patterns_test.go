@@ -0,0 +1,51 @@
+package config
+
+import "testing"
+
+func TestValidateSpecificAlphabet(t *testing.T) {
+	tests := []struct {
+		format AddressFormat
+		sp     SpecificPattern
+		ok     bool
+	}{
+		{FormatEVM, SpecificPattern{Prefix: "beef"}, true},
+		{FormatEVM, SpecificPattern{Prefix: "0xBEEF", Suffix: "cafe"}, true},
+		{FormatEVM, SpecificPattern{Prefix: "beeg"}, false},
+		{FormatEVM, SpecificPattern{Suffix: "0xbeef"}, false},
+		{FormatTron, SpecificPattern{Prefix: "Tron"}, true},
+		{FormatTron, SpecificPattern{Prefix: "0000"}, false},
+	}
+	for _, tt := range tests {
+		c := PatternsConfig{Format: tt.format, Symbols: "1 2 3", Specific: []SpecificPattern{tt.sp}}
+		if err := validate(&c); (err == nil) != tt.ok {
+			t.Errorf("%s %+v: got %v, want ok=%v", tt.format, tt.sp, err, tt.ok)
+		}
+	}
+}
+
+func TestMigrateSpecific(t *testing.T) {
+	c := PatternsConfig{Specific: []SpecificPattern{
+		{Prefix: "0xdead"}, {Prefix: "0"}, {Prefix: "beef"}, {Suffix: "cafe"},
+	}}
+	warns := migrate(&c)
+	if c.Specific[0].Prefix != "dead" {
+		t.Errorf("got prefix %q, want the 0x stripped", c.Specific[0].Prefix)
+	}
+	want := []string{
+		`specific[1].prefix "0" now asks for a body starting with 0; compared against "0x" before, it matched every address`,
+		`specific[2].prefix "beef" now matches the address body; it was compared against "0x" before and could not match`,
+	}
+	if len(warns) != len(want) {
+		t.Fatalf("got warnings %q, want %q", warns, want)
+	}
+	for i := range want {
+		if warns[i] != want[i] {
+			t.Errorf("got %q, want %q", warns[i], want[i])
+		}
+	}
+
+	c = PatternsConfig{Version: BodyViewVersion, Specific: []SpecificPattern{{Prefix: "0"}, {Prefix: "beef"}}}
+	if warns := migrate(&c); len(warns) != 0 {
+		t.Errorf("version 2: got warnings %q", warns)
+	}
+}
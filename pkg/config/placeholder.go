@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PlaceholderPattern pins the start and the end of the address body with
+// variables: every capital letter of Pattern stands for one character, the
+// same one wherever the letter appears, so "ABBA...ABBA" asks for a body
+// that starts and ends with the same two characters mirrored. "?" is any
+// character and every other character is itself. "..." (or "…") separates
+// the start from the end; without it the pattern spans the whole body, like
+// the palindrome "ABCDEFGHIJKLMNOPQRSTTSRQPONMLKJIHGFEDCBA" or the repeated
+// block "ABCDABCD…" of 40 hex digits.
+//
+// Different letters may bind the same character unless Distinct says that
+// all of them differ, or a group of Differ, such as "XZ", says so for its
+// letters.
+type PlaceholderPattern struct {
+	Pattern  string   `yaml:"pattern"`
+	Distinct bool     `yaml:"distinct"`
+	Differ   []string `yaml:"differ"`
+	Final    bool     `yaml:"final"`
+	MaxHits  int      `yaml:"max_hits"`
+}
+
+// PlaceholderSlot is one character of a placeholder pattern: the variable
+// Var ('A'..'Z'), else the literal Char, else any character.
+type PlaceholderSlot struct {
+	Var  byte
+	Char byte
+}
+
+// Parts splits the pattern at its ellipsis into the slots at the start and
+// at the end of the body; without one, all slots are at the start.
+func (p PlaceholderPattern) Parts() (pre, suf []PlaceholderSlot, whole bool, err error) {
+	s := strings.ReplaceAll(p.Pattern, "…", "...")
+	head, tail, split := strings.Cut(s, "...")
+	if strings.Contains(tail, "...") {
+		return nil, nil, false, errors.New("more than one ...")
+	}
+	if pre, err = placeholderSlots(head); err != nil {
+		return nil, nil, false, err
+	}
+	if suf, err = placeholderSlots(tail); err != nil {
+		return nil, nil, false, err
+	}
+	return pre, suf, !split, nil
+}
+
+func placeholderSlots(s string) ([]PlaceholderSlot, error) {
+	out := make([]PlaceholderSlot, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= 'A' && c <= 'Z':
+			out = append(out, PlaceholderSlot{Var: c})
+		case c == '?':
+			out = append(out, PlaceholderSlot{})
+		case c >= 0x80:
+			return nil, fmt.Errorf("%q is neither a variable, ? nor an address character", s[i:])
+		default:
+			out = append(out, PlaceholderSlot{Char: c})
+		}
+	}
+	return out, nil
+}
+
+// Placeholder is the placeholder form of a symmetric pattern: each side is
+// a run of one variable, and the sides share it when they have an X or a Y
+// in common.
+func (p SymmetricPattern) Placeholder() PlaceholderPattern {
+	pre, suf := strings.ToUpper(p.Prefix), strings.ToUpper(p.Suffix)
+	sv := "S"
+	if strings.Contains(pre, "X") && strings.Contains(suf, "X") ||
+		strings.Contains(pre, "Y") && strings.Contains(suf, "Y") {
+		sv = "P"
+	}
+	return PlaceholderPattern{
+		Pattern: strings.Repeat("P", len(pre)) + "..." + strings.Repeat(sv, len(suf)),
+		Final:   p.Final,
+		MaxHits: p.MaxHits,
+	}
+}
+
+// validatePlaceholder checks p against the body of format: literals in its
+// alphabet, the slots within the body, a pattern without ellipsis exactly
+// as long as it, and Differ made of the pattern's variables.
+func validatePlaceholder(p PlaceholderPattern, format AddressFormat, caseSensitive bool) error {
+	pre, suf, whole, err := p.Parts()
+	if err != nil {
+		return err
+	}
+	n := len(pre) + len(suf)
+	switch {
+	case n == 0:
+		return errors.New("empty pattern")
+	case whole && n != format.BodyLen():
+		return fmt.Errorf("pattern has %d characters; without ... it must cover all %d of a %s address body", n, format.BodyLen(), format)
+	case n > format.BodyLen():
+		return fmt.Errorf("pattern has %d characters, a %s address body has %d", n, format, format.BodyLen())
+	}
+	used := map[byte]bool{}
+	for _, s := range append(pre, suf...) {
+		if s.Var != 0 {
+			used[s.Var] = true
+			continue
+		}
+		if s.Char != 0 {
+			if err := format.checkAlphabet(string(s.Char), caseSensitive); err != nil {
+				return fmt.Errorf("%w (capital letters are variables)", err)
+			}
+		}
+	}
+	for i, g := range p.Differ {
+		if len(g) < 2 {
+			return fmt.Errorf("differ[%d]: %q needs at least two variables", i, g)
+		}
+		for j := 0; j < len(g); j++ {
+			if !used[g[j]] {
+				return fmt.Errorf("differ[%d]: %q is not a variable of the pattern", i, g[j])
+			}
+		}
+	}
+	return nil
+}
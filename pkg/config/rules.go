@@ -0,0 +1,252 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Condition is a named test for rules: exactly one pattern of any address
+// kind. Its final and max_hits are not used; those belong to the rules.
+type Condition struct {
+	Placeholder *PlaceholderPattern `yaml:"placeholder"`
+	Specific    *SpecificPattern    `yaml:"specific"`
+	Mask        *MaskPattern        `yaml:"mask"`
+	Words       *WordsPattern       `yaml:"words"`
+	Edges       *EdgeConfig         `yaml:"edges"`
+	Regexp      *RegexpPattern      `yaml:"regexp"`
+}
+
+// Rule combines conditions with AND, OR, NOT and parentheses, e.g.
+// "zeros AND NOT letters". Its hits are filed under Output, by default the
+// rule's name, instead of the kind of a single pattern. Rules are tried in
+// order before the other patterns.
+type Rule struct {
+	Name    string `yaml:"name"`
+	When    string `yaml:"when"`
+	Output  string `yaml:"output"`
+	Final   bool   `yaml:"final"`
+	MaxHits int    `yaml:"max_hits"`
+}
+
+// Kind is where the hits of r are filed.
+func (r Rule) Kind() string {
+	if r.Output != "" {
+		return r.Output
+	}
+	return r.Name
+}
+
+// Expr parses When.
+func (r Rule) Expr() (*Expr, error) { return ParseExpr(r.When) }
+
+// Expr is a parsed rule condition: a named condition when Op is empty, else
+// the AND or OR of Args, or the NOT of its single argument.
+type Expr struct {
+	Op   string // "", "AND", "OR", "NOT"
+	Name string
+	Args []*Expr
+}
+
+// Names lists the conditions e refers to, sorted, without duplicates.
+func (e *Expr) Names() []string {
+	seen := map[string]bool{}
+	var walk func(e *Expr)
+	walk = func(e *Expr) {
+		if e.Op == "" {
+			seen[e.Name] = true
+		}
+		for _, a := range e.Args {
+			walk(a)
+		}
+	}
+	walk(e)
+	out := make([]string, 0, len(seen))
+	for n := range seen {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ParseExpr parses a rule condition. NOT binds tightest, then AND, then OR;
+// the keywords are case-insensitive.
+func ParseExpr(s string) (*Expr, error) {
+	p := exprParser{toks: exprTokens(s)}
+	if len(p.toks) == 0 {
+		return nil, errors.New("empty condition")
+	}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos])
+	}
+	return e, nil
+}
+
+func exprTokens(s string) []string {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+	return strings.Fields(s)
+}
+
+type exprParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) binary(op string, next func() (*Expr, error)) (*Expr, error) {
+	e, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), op) {
+		p.pos++
+		r, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == op {
+			e.Args = append(e.Args, r)
+		} else {
+			e = &Expr{Op: op, Args: []*Expr{e, r}}
+		}
+	}
+	return e, nil
+}
+
+func (p *exprParser) or() (*Expr, error) { return p.binary("OR", p.and) }
+
+func (p *exprParser) and() (*Expr, error) { return p.binary("AND", p.not) }
+
+func (p *exprParser) not() (*Expr, error) {
+	switch tok := p.peek(); {
+	case strings.EqualFold(tok, "NOT"):
+		p.pos++
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &Expr{Op: "NOT", Args: []*Expr{e}}, nil
+	case tok == "(":
+		p.pos++
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return e, nil
+	case tok == "":
+		return nil, errors.New("condition ends early")
+	case tok == ")" || strings.EqualFold(tok, "AND") || strings.EqualFold(tok, "OR"):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		p.pos++
+		return &Expr{Name: tok}, nil
+	}
+}
+
+// patternKinds are the kinds the other patterns file their hits under; no
+// rule output may take one of them.
+var patternKinds = []string{"symmetric", "placeholder", "specific", "mask", "words", "edges", "regexp", "mnemonic"}
+
+// ruleName is what the names of rules, outputs and conditions look like;
+// the first two become file names of the run directory.
+var ruleName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+func validateRules(c *PatternsConfig) error {
+	for name, cond := range c.Conditions {
+		switch {
+		case !ruleName.MatchString(name):
+			return fmt.Errorf("conditions.%s: names start with a letter and use letters, digits, _ and -", name)
+		case strings.EqualFold(name, "AND") || strings.EqualFold(name, "OR") || strings.EqualFold(name, "NOT"):
+			return fmt.Errorf("conditions.%s: AND, OR and NOT are operators", name)
+		}
+		if err := validateCondition(cond, c); err != nil {
+			return fmt.Errorf("conditions.%s: %w", name, err)
+		}
+	}
+
+	names := map[string]bool{}
+	for i, r := range c.Rules {
+		switch {
+		case !ruleName.MatchString(r.Name):
+			return fmt.Errorf("rules[%d].name %q: names start with a letter and use letters, digits, _ and -", i, r.Name)
+		case names[r.Name]:
+			return fmt.Errorf("rules[%d].name %q is used twice", i, r.Name)
+		case r.Output != "" && !ruleName.MatchString(r.Output):
+			return fmt.Errorf("rules[%d].output %q: names start with a letter and use letters, digits, _ and -", i, r.Output)
+		case strings.EqualFold(r.Kind(), "app"):
+			return fmt.Errorf("rules[%d]: output app is the log of the run", i)
+		case slices.ContainsFunc(patternKinds, func(k string) bool { return strings.EqualFold(k, r.Kind()) }):
+			return fmt.Errorf("rules[%d]: output %s is where the %s patterns file their hits", i, r.Kind(), strings.ToLower(r.Kind()))
+		case r.MaxHits < 0:
+			return fmt.Errorf("rules[%d].max_hits must be >= 0", i)
+		}
+		names[r.Name] = true
+		e, err := r.Expr()
+		if err != nil {
+			return fmt.Errorf("rules[%d].when: %w", i, err)
+		}
+		for _, n := range e.Names() {
+			if _, ok := c.Conditions[n]; !ok {
+				return fmt.Errorf("rules[%d].when: no condition named %q", i, n)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCondition applies the checks of the pattern's own section.
+func validateCondition(cond Condition, c *PatternsConfig) error {
+	n := 0
+	for _, set := range []bool{cond.Placeholder != nil, cond.Specific != nil, cond.Mask != nil, cond.Words != nil, cond.Edges != nil, cond.Regexp != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("has %d patterns, want exactly one of placeholder, specific, mask, words, edges, regexp", n)
+	}
+
+	switch {
+	case cond.Placeholder != nil:
+		return validatePlaceholder(*cond.Placeholder, c.Format, c.CaseSensitive)
+	case cond.Specific != nil:
+		if cond.Specific.Prefix == "" && cond.Specific.Suffix == "" {
+			return errors.New("specific needs a prefix or a suffix")
+		}
+		return c.checkSpecific(*cond.Specific)
+	case cond.Mask != nil:
+		return validateMask(*cond.Mask, c.Format, c.CaseSensitive)
+	case cond.Words != nil:
+		return validateWords(*cond.Words)
+	case cond.Edges != nil:
+		switch cond.Edges.Side {
+		case "any", "prefix", "suffix":
+		default:
+			return errors.New("edges.side must be one of: any, prefix, suffix")
+		}
+		if cond.Edges.MinCount < 1 {
+			return errors.New("edges.minCount must be >= 1")
+		}
+	case cond.Regexp != nil:
+		_, err := regexp.Compile(cond.Regexp.Pattern)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// exprString writes e with prefix operators, e.g. OR(a AND(b NOT(c))).
+func exprString(e *Expr) string {
+	if e.Op == "" {
+		return e.Name
+	}
+	args := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		args[i] = exprString(a)
+	}
+	return e.Op + "(" + strings.Join(args, " ") + ")"
+}
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"a", "a"},
+		{"a OR b AND NOT c", "OR(a AND(b NOT(c)))"},
+		{"NOT a AND b OR c", "OR(AND(NOT(a) b) c)"},
+		{"(a OR b) AND c", "AND(OR(a b) c)"},
+		{"NOT (a OR b)", "NOT(OR(a b))"},
+		{"a and b AND c or d", "OR(AND(a b c) d)"},
+		{"NOT NOT a", "NOT(NOT(a))"},
+		{"((a))", "a"},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if got := exprString(e); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "a AND", "(a", "a b", "AND a", "a )", "()", "NOT"} {
+		if e, err := ParseExpr(in); err == nil {
+			t.Errorf("%q: got %s, want an error", in, exprString(e))
+		}
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	conds := map[string]Condition{
+		"zeros": {Specific: &SpecificPattern{Prefix: "0000"}},
+		"beef":  {Specific: &SpecificPattern{Suffix: "beef"}},
+	}
+	tests := []struct {
+		name  string
+		conds map[string]Condition
+		rules []Rule
+		err   string // part of the error, "" for none
+	}{
+		{"ok", conds, []Rule{{Name: "snack", When: "zeros AND NOT beef"}}, ""},
+		{"unknown condition", conds, []Rule{{Name: "snack", When: "zeros AND cafe"}}, `no condition named "cafe"`},
+		{"app output", conds, []Rule{{Name: "snack", When: "zeros", Output: "app"}}, "log of the run"},
+		{"kind output", conds, []Rule{{Name: "snack", When: "zeros", Output: "specific"}}, "specific patterns"},
+		{"kind name", conds, []Rule{{Name: "Words", When: "zeros"}}, "words patterns"},
+		{"twice", conds, []Rule{{Name: "a", When: "zeros"}, {Name: "a", When: "beef"}}, "used twice"},
+		{"operator condition", map[string]Condition{"not": conds["zeros"]}, nil, "operators"},
+		{"two patterns", map[string]Condition{"x": {Specific: conds["zeros"].Specific, Regexp: &RegexpPattern{Pattern: "a"}}}, nil, "has 2 patterns"},
+		{"bad hex", map[string]Condition{"x": {Specific: &SpecificPattern{Prefix: "beeg"}}}, nil, `'g'`},
+		// The first problem is reported, whatever the order of the checks.
+		{"edges side", map[string]Condition{"x": {Edges: &EdgeConfig{Side: "middle"}}}, nil, "edges.side"},
+		{"edges count", map[string]Condition{"x": {Edges: &EdgeConfig{Side: "prefix"}}}, nil, "edges.minCount"},
+	}
+	for _, tt := range tests {
+		c := PatternsConfig{Format: FormatEVM, Conditions: tt.conds, Rules: tt.rules}
+		err := validateRules(&c)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: got %v, want an error with %s", tt.name, err, tt.err)
+		}
+	}
+}
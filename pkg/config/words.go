@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WordsPattern matches an address whose body contains any word of a
+// dictionary: the words of List and of File, one per line, where "#" starts
+// a comment. Side anchors the word at the start (prefix) or the end (suffix)
+// of the body; any, the default, finds it anywhere. Words shorter than
+// MinLength are dropped.
+//
+// With Leet, characters the address alphabet lacks are replaced by their
+// leetspeak digits (o→0, i and l→1, z→2, e→3, a→4, s→5, g→9, t→7, b→8), so
+// that "coffee" becomes the hexspeak "c0ffee". Words that still do not fit
+// the alphabet, or are longer than the body, are skipped with a warning.
+type WordsPattern struct {
+	File      string   `yaml:"file"` // relative to the directory of the config
+	List      []string `yaml:"list"`
+	Side      string   `yaml:"side"` // any | prefix | suffix
+	MinLength int      `yaml:"min_length"`
+	Leet      bool     `yaml:"leet"`
+	Final     bool     `yaml:"final"`
+	MaxHits   int      `yaml:"max_hits"`
+
+	// Words is the dictionary as matched, set by LoadFor: leet applied,
+	// lower-case unless the case counts, without duplicates.
+	Words []string `yaml:"-"`
+}
+
+// leet maps a letter to the digit that stands in for it.
+var leet = map[byte]byte{
+	'o': '0', 'i': '1', 'l': '1', 'z': '2', 'e': '3',
+	'a': '4', 's': '5', 'g': '9', 't': '7', 'b': '8',
+}
+
+// loadWords reads the dictionary of p and fills p.Words for addresses in
+// format, returning the words that had to be skipped.
+func loadWords(p *WordsPattern, dir string, format AddressFormat, keepCase bool) ([]string, error) {
+	raw := append([]string(nil), p.List...)
+	if p.File != "" {
+		path := p.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open dictionary: %w", err)
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			line, _, _ := strings.Cut(sc.Text(), "#")
+			if line = strings.TrimSpace(line); line != "" {
+				raw = append(raw, line)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("read dictionary %q: %w", path, err)
+		}
+	}
+
+	var skipped []string
+	seen := make(map[string]bool, len(raw))
+	p.Words = p.Words[:0]
+	for _, w := range raw {
+		w = strings.TrimSpace(w)
+		if !keepCase {
+			w = strings.ToLower(w)
+		}
+		if p.Leet {
+			w = leetWord(w, format, keepCase)
+		}
+		if w == "" || len(w) < p.MinLength || seen[w] {
+			continue
+		}
+		if len(w) > format.BodyLen() || format.checkAlphabet(w, keepCase) != nil {
+			skipped = append(skipped, w)
+			continue
+		}
+		seen[w] = true
+		p.Words = append(p.Words, w)
+	}
+	return skipped, nil
+}
+
+// leetWord replaces the characters of w that are not in the alphabet of
+// format by their leetspeak digits, where the alphabet has those.
+func leetWord(w string, format AddressFormat, keepCase bool) string {
+	b := []byte(w)
+	for i, c := range b {
+		if format.checkAlphabet(string(c), keepCase) == nil {
+			continue
+		}
+		if d, ok := leet[c|0x20]; ok && format.checkAlphabet(string(d), keepCase) == nil {
+			b[i] = d
+		}
+	}
+	return string(b)
+}
+
+func validateWords(p WordsPattern) error {
+	if p.MaxHits < 0 {
+		return errors.New("max_hits must be >= 0")
+	}
+	if p.MinLength < 0 {
+		return errors.New("min_length must be >= 0")
+	}
+	switch p.Side {
+	case "", "any", "prefix", "suffix":
+	default:
+		return errors.New("side must be one of: any, prefix, suffix")
+	}
+	if len(p.Words) == 0 {
+		return errors.New("no word of the dictionary can appear in an address")
+	}
+	return nil
+}
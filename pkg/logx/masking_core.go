@@ -2,7 +2,10 @@ package logx
 
 import (
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -17,6 +20,50 @@ type maskingCore struct {
 	replaceValue string
 }
 
+// secrets are values registered by AddSecret.
+var secrets struct {
+	sync.RWMutex
+	values []string
+}
+
+// MinSecretLength is the fewest characters of a value AddSecret registers.
+// A shorter one would redact ordinary words and numbers along with it; such
+// values are left to the sensitive field keys.
+const MinSecretLength = 8
+
+// AddSecret registers a value that the masking core redacts wherever it
+// appears, in messages and in string fields under any key. It is for secrets
+// that no field name gives away, like generated passphrases. Registering a
+// value twice, or one under MinSecretLength characters, is a no-op.
+func AddSecret(v string) {
+	if utf8.RuneCountInString(v) < MinSecretLength {
+		return
+	}
+	secrets.Lock()
+	if !slices.Contains(secrets.values, v) {
+		secrets.values = append(secrets.values, v)
+	}
+	secrets.Unlock()
+}
+
+// ClearSecrets forgets the values registered by AddSecret.
+func ClearSecrets() {
+	secrets.Lock()
+	clear(secrets.values)
+	secrets.values = nil
+	secrets.Unlock()
+}
+
+// redactSecrets replaces the registered values in s.
+func (m *maskingCore) redactSecrets(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+	for _, v := range secrets.values {
+		s = strings.ReplaceAll(s, v, m.replaceValue)
+	}
+	return s
+}
+
 func (m *maskingCore) cloneFieldsWithRedaction(fields []zapcore.Field) []zapcore.Field {
 	if len(fields) == 0 {
 		return fields
@@ -28,13 +75,39 @@ func (m *maskingCore) cloneFieldsWithRedaction(fields []zapcore.Field) []zapcore
 			out = append(out, zap.String(f.Key, m.replaceValue))
 			continue
 		}
+		switch f.Type {
+		case zapcore.StringType:
+			f.String = m.redactSecrets(f.String)
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok {
+				if msg := m.redactSecrets(err.Error()); msg != err.Error() {
+					f = zap.String(f.Key, msg)
+				}
+			}
+		}
 		out = append(out, f)
 	}
 	return out
 }
 
+// Check and With must be overridden: the embedded core would register
+// itself, and entries would bypass Write.
+func (m *maskingCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if m.Enabled(entry.Level) {
+		return ce.AddCore(entry, m)
+	}
+	return ce
+}
+
+func (m *maskingCore) With(fields []zapcore.Field) zapcore.Core {
+	c := *m
+	c.Core = m.Core.With(m.cloneFieldsWithRedaction(fields))
+	return &c
+}
+
 func (m *maskingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
 	// mask message text
+	entry.Message = m.redactSecrets(entry.Message)
 	if m.maskPattern != nil && entry.Message != "" {
 		entry.Message = m.maskPattern.ReplaceAllString(entry.Message, m.replaceValue)
 	}
@@ -47,7 +120,7 @@ func defaultSensitiveKeys() map[string]struct{} {
 	keys := []string{
 		"private", "private_key", "privatekey",
 		"priv", "secret", "mnemonic", "seed", "passphrase",
-		"raw", "raw_key", "raw_private", "key",
+		"raw", "raw_key", "raw_private", "key", "wif", "secret_key", "keypair",
 	}
 	m := make(map[string]struct{}, len(keys))
 	for _, k := range keys {
@@ -0,0 +1,24 @@
+package logx
+
+import "testing"
+
+func TestAddSecret(t *testing.T) {
+	defer ClearSecrets()
+	m := &maskingCore{replaceValue: "[REDACTED]"}
+
+	AddSecret("test")          // too short: would hit ordinary text
+	AddSecret("correct horse") // registered
+	AddSecret("correct horse") // and only once
+	if n := len(secrets.values); n != 1 {
+		t.Fatalf("got %d secrets, want 1", n)
+	}
+	const in = "test run: correct horse at m/44'/60'/0'/0/1"
+	if got, want := m.redactSecrets(in), "test run: [REDACTED] at m/44'/60'/0'/0/1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	ClearSecrets()
+	if got := m.redactSecrets(in); got != in {
+		t.Errorf("after ClearSecrets: got %q", got)
+	}
+}